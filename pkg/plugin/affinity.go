@@ -0,0 +1,577 @@
+package plugin
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var affinityShort = "List the nodeSelector, affinity, tolerations and topology spread of each pod"
+
+var affinityDescription = ` Prints one row for each scheduling constraint set on a pod, this includes nodeSelector entries,
+required and preferred node affinity terms, pod affinity and anti-affinity terms, tolerations and
+topology spread constraints. Terms that contain more than one requirement are shown as one row
+per requirement with the same TERM number, requirements in the same term must all match.
+
+When using the tree view each owner shows how its pods are distributed across the topology key of
+each topology spread constraint, the SKEW column shows the difference between the domain with the
+most pods and the domain with the least pods, domains are read from the labels of the nodes the
+pods can be scheduled on. Nodes that dont match the nodeSelector or required node affinity, or
+have a taint the pods dont tolerate, are skipped.`
+
+var affinityExample = `  # List the scheduling constraints of all pods in the current namespace
+  %[1]s affinity
+
+  # List the scheduling constraints of pods output in JSON format
+  %[1]s affinity -o json
+
+  # List only the tolerations of a single pod
+  %[1]s affinity my-pod-4jh36 --match CONSTRAINT==toleration
+
+  # Show the zone distribution and skew of each deployment
+  %[1]s affinity --tree
+
+  # List the scheduling constraints of all pods where the pod label app is either web or mail
+  %[1]s affinity -l "app in (web,mail)"`
+
+func Affinity(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Affinity"}
+	log.Debug("Start")
+
+	loopinfo := affinity{}
+	builder := RowBuilder{}
+	builder.DontListContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	// node labels are needed to calculate the distribution and can only be read from a cluster
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+	if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
+		loopinfo.Connection = &connect
+	}
+
+	table := Table{}
+	table.ColourOutput = commonFlagList.outputAsColour
+	table.CustomColours = commonFlagList.useTheseColours
+
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type affinity struct {
+	Connection *Connector // nil when the pods are read from a file
+}
+
+func (s *affinity) Headers() []string {
+	return []string{
+		"CONSTRAINT", "MODE", "TERM", "KEY", "OPERATOR", "VALUES", "EFFECT", "SECONDS", "WEIGHT", "TOPOLOGY", "MAX-SKEW", "DISTRIBUTION", "SKEW",
+	}
+}
+
+func (s *affinity) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *affinity) BuildEphemeralContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *affinity) HideColumns(info BuilderInformation) []int {
+	// distribution and skew are only calculated on the tree branches
+	if !info.TreeView {
+		return []int{11, 12}
+	}
+	return []int{}
+}
+
+// BuildBranch works out the topology domain each pod is running in, owner rows add up the pods in
+// each domain and calculate the skew
+func (s *affinity) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, len(s.Headers()))
+	for i := range rowOut {
+		rowOut[i] = NewCellText("")
+	}
+
+	if info.TypeName == TypeNamePod {
+		pod := info.Data.pod
+		nodeLabels := s.nodeLabels(pod.Spec.NodeName)
+
+		maxSkew := make(map[string]int64)
+		counts := make(map[string]map[string]int64)
+		for _, constraint := range pod.Spec.TopologySpreadConstraints {
+			maxSkew[constraint.TopologyKey] = int64(constraint.MaxSkew)
+			if counts[constraint.TopologyKey] == nil {
+				counts[constraint.TopologyKey] = make(map[string]int64)
+			}
+			if value, ok := nodeLabels[constraint.TopologyKey]; ok {
+				counts[constraint.TopologyKey][value]++
+			}
+		}
+
+		rowOut[10] = NewCellText(affinityFormatMaxSkew(maxSkew))
+		rowOut[11] = NewCellText(affinityFormatDistribution(counts))
+		return rowOut, nil
+	}
+
+	// merge the distribution from each child, the smallest maxSkew is used when the children disagree
+	maxSkew := make(map[string]int64)
+	counts := make(map[string]map[string]int64)
+	for _, r := range rows {
+		for key, skew := range affinityParseMaxSkew(r[10].text) {
+			if current, ok := maxSkew[key]; !ok || skew < current {
+				maxSkew[key] = skew
+			}
+		}
+		for key, domains := range affinityParseDistribution(r[11].text) {
+			if counts[key] == nil {
+				counts[key] = make(map[string]int64)
+			}
+			for domain, count := range domains {
+				counts[key][domain] += count
+			}
+		}
+	}
+
+	if len(counts) == 0 {
+		return rowOut, nil
+	}
+
+	keys := affinitySortedKeys(counts)
+	skewList := []string{}
+	var worstSkew int64
+	overLimit := false
+	for _, key := range keys {
+		skew := affinitySkew(counts[key], s.nodeDomains(key, info.Data))
+		skewList = append(skewList, fmt.Sprintf("%s:%d", key, skew))
+		if skew > worstSkew {
+			worstSkew = skew
+		}
+		if skew > maxSkew[key] {
+			overLimit = true
+		}
+	}
+
+	rowOut[10] = NewCellText(affinityFormatMaxSkew(maxSkew))
+	rowOut[11] = NewCellText(affinityFormatDistribution(counts))
+	rowOut[12] = NewCellColourInt(setColourBoolean(!overLimit), strings.Join(skewList, ","), worstSkew)
+	return rowOut, nil
+}
+
+func (s *affinity) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *affinity) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *affinity) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
+	return affinityBuildRows(pod), nil
+}
+
+// nodeLabels returns the labels of the named node, nil is returned when not connected to a cluster
+func (s *affinity) nodeLabels(nodeName string) map[string]string {
+	if s.Connection == nil {
+		return nil
+	}
+
+	for _, node := range s.Connection.GetNodeList() {
+		if node.Name == nodeName {
+			return node.Labels
+		}
+	}
+	return nil
+}
+
+// nodeDomains returns every value of the topology key found on the nodes the owners pods can be
+// scheduled on, nodes excluded by the nodeSelector, required node affinity or an untolerated taint
+// are skipped as they can never hold a pod. When the owner has no pod template every node is used
+func (s *affinity) nodeDomains(topologyKey string, owner ParentData) []string {
+	if s.Connection == nil {
+		return []string{}
+	}
+
+	template, hasTemplate := ownerPodTemplate(owner)
+	return affinityNodeDomains(s.Connection.GetNodeList(), topologyKey, v1.Pod{Spec: template}, hasTemplate)
+}
+
+// affinityNodeDomains returns the unique values of the topology key on the nodes, when checkPod is
+// true only the nodes the pod is eligible to run on are included
+func affinityNodeDomains(nodes []v1.Node, topologyKey string, pod v1.Pod, checkPod bool) []string {
+	domains := []string{}
+	for _, node := range nodes {
+		value, ok := node.Labels[topologyKey]
+		if !ok {
+			continue
+		}
+
+		if checkPod {
+			if len(schedulingCheckNodeSelector(pod, node)) > 0 ||
+				len(schedulingCheckNodeAffinity(pod, node)) > 0 ||
+				len(schedulingCheckTaints(pod, node)) > 0 {
+				continue
+			}
+		}
+
+		if !slices.Contains(domains, value) {
+			domains = append(domains, value)
+		}
+	}
+	return domains
+}
+
+// affinityRow holds the columns of a single constraint row
+type affinityRow struct {
+	constraint string
+	mode       string
+	term       int
+	key        string
+	operator   string
+	values     string
+	effect     string
+	seconds    *int64
+	weight     int32
+	topology   string
+	maxSkew    int32
+}
+
+func (r affinityRow) cells() []Cell {
+	seconds := NewCellText("")
+	if r.seconds != nil {
+		seconds = NewCellInt(strconv.FormatInt(*r.seconds, 10), *r.seconds)
+	}
+
+	weight := NewCellText("")
+	if r.weight > 0 {
+		weight = NewCellInt(strconv.Itoa(int(r.weight)), int64(r.weight))
+	}
+
+	maxSkew := NewCellText("")
+	if r.maxSkew > 0 {
+		maxSkew = NewCellInt(strconv.Itoa(int(r.maxSkew)), int64(r.maxSkew))
+	}
+
+	return []Cell{
+		NewCellText(r.constraint),
+		NewCellText(r.mode),
+		NewCellInt(strconv.Itoa(r.term), int64(r.term)),
+		NewCellText(r.key),
+		NewCellText(r.operator),
+		NewCellText(r.values),
+		NewCellText(r.effect),
+		seconds,
+		weight,
+		NewCellText(r.topology),
+		maxSkew,
+		NewCellText(""),
+		NewCellText(""),
+	}
+}
+
+// affinityBuildRows returns a row for each scheduling constraint in the pod spec
+func affinityBuildRows(pod v1.Pod) [][]Cell {
+	rows := []affinityRow{}
+
+	keys := []string{}
+	for key := range pod.Spec.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		rows = append(rows, affinityRow{constraint: "nodeSelector", mode: "required", key: key, operator: "=", values: pod.Spec.NodeSelector[key]})
+	}
+
+	if pod.Spec.Affinity != nil {
+		rows = append(rows, affinityNodeAffinityRows(pod.Spec.Affinity.NodeAffinity)...)
+		if pod.Spec.Affinity.PodAffinity != nil {
+			rows = append(rows, affinityPodAffinityRows("podAffinity",
+				pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+				pod.Spec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution)...)
+		}
+		if pod.Spec.Affinity.PodAntiAffinity != nil {
+			rows = append(rows, affinityPodAffinityRows("podAntiAffinity",
+				pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+				pod.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)...)
+		}
+	}
+
+	for i, toleration := range pod.Spec.Tolerations {
+		operator := string(toleration.Operator)
+		if len(operator) == 0 {
+			operator = string(v1.TolerationOpEqual)
+		}
+		rows = append(rows, affinityRow{
+			constraint: "toleration",
+			term:       i,
+			key:        toleration.Key,
+			operator:   operator,
+			values:     toleration.Value,
+			effect:     string(toleration.Effect),
+			seconds:    toleration.TolerationSeconds,
+		})
+	}
+
+	for i, constraint := range pod.Spec.TopologySpreadConstraints {
+		for _, row := range affinityLabelSelectorRows(constraint.LabelSelector) {
+			row.constraint = "topologySpread"
+			row.mode = string(constraint.WhenUnsatisfiable)
+			row.term = i
+			row.topology = constraint.TopologyKey
+			row.maxSkew = constraint.MaxSkew
+			rows = append(rows, row)
+		}
+	}
+
+	out := [][]Cell{}
+	for _, row := range rows {
+		out = append(out, row.cells())
+	}
+	return out
+}
+
+// affinityNodeAffinityRows returns a row for each requirement in the required and preferred terms
+func affinityNodeAffinityRows(nodeAffinity *v1.NodeAffinity) []affinityRow {
+	rows := []affinityRow{}
+	if nodeAffinity == nil {
+		return rows
+	}
+
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		for i, term := range nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			for _, row := range affinityNodeSelectorTermRows(term) {
+				row.mode = "required"
+				row.term = i
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	for i, preferred := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		for _, row := range affinityNodeSelectorTermRows(preferred.Preference) {
+			row.mode = "preferred"
+			row.term = i
+			row.weight = preferred.Weight
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+func affinityNodeSelectorTermRows(term v1.NodeSelectorTerm) []affinityRow {
+	rows := []affinityRow{}
+	for _, req := range term.MatchExpressions {
+		rows = append(rows, affinityRow{constraint: "nodeAffinity", key: req.Key, operator: string(req.Operator), values: strings.Join(req.Values, ",")})
+	}
+	for _, req := range term.MatchFields {
+		rows = append(rows, affinityRow{constraint: "nodeAffinity", key: req.Key, operator: string(req.Operator), values: strings.Join(req.Values, ",")})
+	}
+	return rows
+}
+
+// affinityPodAffinityRows returns a row for each label requirement in the pod affinity terms
+func affinityPodAffinityRows(constraint string, required []v1.PodAffinityTerm, preferred []v1.WeightedPodAffinityTerm) []affinityRow {
+	rows := []affinityRow{}
+
+	for i, term := range required {
+		for _, row := range affinityLabelSelectorRows(term.LabelSelector) {
+			row.constraint = constraint
+			row.mode = "required"
+			row.term = i
+			row.topology = term.TopologyKey
+			rows = append(rows, row)
+		}
+	}
+
+	for i, weighted := range preferred {
+		for _, row := range affinityLabelSelectorRows(weighted.PodAffinityTerm.LabelSelector) {
+			row.constraint = constraint
+			row.mode = "preferred"
+			row.term = i
+			row.weight = weighted.Weight
+			row.topology = weighted.PodAffinityTerm.TopologyKey
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+// affinityLabelSelectorRows converts a label selector to a row per requirement, an empty or
+// missing selector is returned as a single row with no key
+func affinityLabelSelectorRows(selector *metav1.LabelSelector) []affinityRow {
+	rows := []affinityRow{}
+	if selector == nil {
+		return []affinityRow{{}}
+	}
+
+	keys := []string{}
+	for key := range selector.MatchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		rows = append(rows, affinityRow{key: key, operator: "=", values: selector.MatchLabels[key]})
+	}
+
+	for _, req := range selector.MatchExpressions {
+		rows = append(rows, affinityRow{key: req.Key, operator: string(req.Operator), values: strings.Join(req.Values, ",")})
+	}
+
+	if len(rows) == 0 {
+		return []affinityRow{{}}
+	}
+	return rows
+}
+
+// affinitySkew returns the difference between the domain with the most pods and the domain with
+// the least pods, domains from the node list that have no pods count as zero
+func affinitySkew(counts map[string]int64, domains []string) int64 {
+	all := make(map[string]int64)
+	for _, domain := range domains {
+		all[domain] = 0
+	}
+	for domain, count := range counts {
+		all[domain] += count
+	}
+
+	if len(all) == 0 {
+		return 0
+	}
+
+	first := true
+	var minCount, maxCount int64
+	for _, count := range all {
+		if first || count < minCount {
+			minCount = count
+		}
+		if first || count > maxCount {
+			maxCount = count
+		}
+		first = false
+	}
+
+	return maxCount - minCount
+}
+
+// affinityFormatDistribution formats the pod counts as key=domain:count separated by commas
+func affinityFormatDistribution(counts map[string]map[string]int64) string {
+	list := []string{}
+	for _, key := range affinitySortedKeys(counts) {
+		domains := []string{}
+		for domain := range counts[key] {
+			domains = append(domains, domain)
+		}
+		sort.Strings(domains)
+		for _, domain := range domains {
+			list = append(list, fmt.Sprintf("%s=%s:%d", key, domain, counts[key][domain]))
+		}
+	}
+	return strings.Join(list, ",")
+}
+
+// affinityParseDistribution reverses affinityFormatDistribution
+func affinityParseDistribution(distribution string) map[string]map[string]int64 {
+	counts := make(map[string]map[string]int64)
+	if len(distribution) == 0 {
+		return counts
+	}
+
+	for _, item := range strings.Split(distribution, ",") {
+		idx := strings.LastIndex(item, ":")
+		eq := strings.Index(item, "=")
+		if idx < 0 || eq < 0 || eq > idx {
+			continue
+		}
+		count, err := strconv.ParseInt(item[idx+1:], 10, 64)
+		if err != nil {
+			continue
+		}
+		key := item[:eq]
+		if counts[key] == nil {
+			counts[key] = make(map[string]int64)
+		}
+		counts[key][item[eq+1:idx]] += count
+	}
+	return counts
+}
+
+// affinityFormatMaxSkew formats the maxSkew of each topology key as key=maxSkew
+func affinityFormatMaxSkew(maxSkew map[string]int64) string {
+	keys := []string{}
+	for key := range maxSkew {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := []string{}
+	for _, key := range keys {
+		list = append(list, fmt.Sprintf("%s=%d", key, maxSkew[key]))
+	}
+	return strings.Join(list, ",")
+}
+
+// affinityParseMaxSkew reverses affinityFormatMaxSkew
+func affinityParseMaxSkew(text string) map[string]int64 {
+	maxSkew := make(map[string]int64)
+	if len(text) == 0 {
+		return maxSkew
+	}
+
+	for _, item := range strings.Split(text, ",") {
+		idx := strings.LastIndex(item, "=")
+		if idx < 0 {
+			continue
+		}
+		skew, err := strconv.ParseInt(item[idx+1:], 10, 64)
+		if err != nil {
+			continue
+		}
+		maxSkew[item[:idx]] = skew
+	}
+	return maxSkew
+}
+
+func affinitySortedKeys(counts map[string]map[string]int64) []string {
+	keys := []string{}
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
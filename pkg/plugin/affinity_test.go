@@ -0,0 +1,182 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// *****************
+// affinityBuildRows
+// *****************
+
+func TestAffinityBuildRows(t *testing.T) {
+	seconds := int64(300)
+	pod := v1.Pod{Spec: v1.PodSpec{
+		NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+		Affinity: &v1.Affinity{
+			NodeAffinity: &v1.NodeAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{
+					{Weight: 20, Preference: v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{
+						{Key: "disktype", Operator: v1.NodeSelectorOpIn, Values: []string{"ssd", "nvme"}},
+					}}},
+				},
+			},
+			PodAffinity: &v1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
+					{TopologyKey: "kubernetes.io/hostname"},
+				},
+			},
+		},
+		Tolerations: []v1.Toleration{
+			{Key: "node.kubernetes.io/unreachable", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute, TolerationSeconds: &seconds},
+		},
+		TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+			{MaxSkew: 2, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.ScheduleAnyway, LabelSelector: &metav1.LabelSelector{
+				MatchLabels:      map[string]string{"app": "web"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"db"}}},
+			}},
+		},
+	}}
+
+	// constraint, mode, key, operator, values, effect, seconds, weight, topology, max-skew
+	expected := [][]string{
+		{"nodeSelector", "required", "kubernetes.io/os", "=", "linux", "", "", "", "", ""},
+		{"nodeAffinity", "preferred", "disktype", "In", "ssd,nvme", "", "", "20", "", ""},
+		{"podAffinity", "required", "", "", "", "", "", "", "kubernetes.io/hostname", ""},
+		{"toleration", "", "node.kubernetes.io/unreachable", "Exists", "", "NoExecute", "300", "", "", ""},
+		{"topologySpread", "ScheduleAnyway", "app", "=", "web", "", "", "", "topology.kubernetes.io/zone", "2"},
+		{"topologySpread", "ScheduleAnyway", "tier", "NotIn", "db", "", "", "", "topology.kubernetes.io/zone", "2"},
+	}
+
+	output := affinityBuildRows(pod)
+	if len(output) != len(expected) {
+		t.Fatalf("Output has %d rows expected %d", len(output), len(expected))
+	}
+
+	columns := []int{0, 1, 3, 4, 5, 6, 7, 8, 9, 10}
+	for i, row := range expected {
+		for j, col := range columns {
+			if output[i][col].text != row[j] {
+				t.Errorf("Output row %d column %d %s not equal to expected %s", i, col, output[i][col].text, row[j])
+			}
+		}
+	}
+}
+
+// *****************
+// affinitySkew
+// *****************
+
+func TestAffinitySkew(t *testing.T) {
+	tests := []struct {
+		counts   map[string]int64
+		domains  []string
+		expected int64
+	}{
+		{map[string]int64{"a": 2, "b": 2}, []string{"a", "b"}, 0},
+		{map[string]int64{"a": 3, "b": 1}, []string{"a", "b"}, 2},
+		{map[string]int64{"a": 2, "b": 2}, []string{"a", "b", "c"}, 2},
+		{map[string]int64{"a": 1}, []string{}, 0},
+		{map[string]int64{}, []string{}, 0},
+	}
+
+	for _, test := range tests {
+		output := affinitySkew(test.counts, test.domains)
+		if output != test.expected {
+			t.Errorf("Output %d not equal to expected %d, for %v", output, test.expected, test.counts)
+		}
+	}
+}
+
+// *****************
+// BuildBranch
+// *****************
+
+func TestAffinityBuildBranchOwner(t *testing.T) {
+	s := affinity{}
+
+	pod := func(distribution string) []Cell {
+		row := make([]Cell, len(s.Headers()))
+		row[10] = NewCellText("topology.kubernetes.io/zone=1")
+		row[11] = NewCellText(distribution)
+		return row
+	}
+
+	rows := [][]Cell{
+		pod("topology.kubernetes.io/zone=a:1"),
+		pod("topology.kubernetes.io/zone=a:1"),
+		pod("topology.kubernetes.io/zone=b:1"),
+		pod("topology.kubernetes.io/zone=a:1"),
+	}
+
+	output, _ := s.BuildBranch(BuilderInformation{TypeName: "ReplicaSet"}, rows)
+	if output[11].text != "topology.kubernetes.io/zone=a:3,topology.kubernetes.io/zone=b:1" {
+		t.Errorf("Output distribution %s not equal to expected", output[11].text)
+	}
+	if output[12].text != "topology.kubernetes.io/zone:2" {
+		t.Errorf("Output skew %s not equal to expected", output[12].text)
+	}
+	if output[12].colour != colourBad {
+		t.Errorf("Output skew colour %v not equal to expected %v", output[12].colour, colourBad)
+	}
+
+	// the distribution of an owner is merged into its parent
+	parent, _ := s.BuildBranch(BuilderInformation{TypeName: "Deployment"}, [][]Cell{output, pod("topology.kubernetes.io/zone=b:1")})
+	if parent[12].text != "topology.kubernetes.io/zone:1" || parent[12].colour != colourOk {
+		t.Errorf("Output parent skew %s not equal to expected", parent[12].text)
+	}
+}
+
+// *****************
+// affinityNodeDomains
+// *****************
+
+func TestAffinityNodeDomains(t *testing.T) {
+	zone := "topology.kubernetes.io/zone"
+	node := func(name string, value string, labels map[string]string, taints []v1.Taint) v1.Node {
+		all := map[string]string{zone: value}
+		for k, v := range labels {
+			all[k] = v
+		}
+		return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: all}, Spec: v1.NodeSpec{Taints: taints}}
+	}
+	nodes := []v1.Node{
+		node("node-a", "a", map[string]string{"disk": "ssd"}, nil),
+		node("node-b", "b", map[string]string{"disk": "hdd"}, nil),
+		node("node-c", "c", map[string]string{"disk": "ssd"}, []v1.Taint{{Key: "gpu", Effect: v1.TaintEffectNoSchedule}}),
+		node("node-d", "d", map[string]string{"disk": "ssd", "arch": "arm64"}, nil),
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-e"}},
+	}
+
+	affinity := &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+			MatchExpressions: []v1.NodeSelectorRequirement{{Key: "arch", Operator: v1.NodeSelectorOpDoesNotExist}},
+		}}},
+	}}
+
+	tests := []struct {
+		name     string
+		spec     v1.PodSpec
+		checkPod bool
+		expected []string
+	}{
+		{"every node", v1.PodSpec{}, false, []string{"a", "b", "c", "d"}},
+		{"untolerated taint", v1.PodSpec{}, true, []string{"a", "b", "d"}},
+		{"node selector", v1.PodSpec{NodeSelector: map[string]string{"disk": "ssd"}}, true, []string{"a", "d"}},
+		{"node affinity", v1.PodSpec{NodeSelector: map[string]string{"disk": "ssd"}, Affinity: affinity}, true, []string{"a"}},
+		{"tolerated taint", v1.PodSpec{
+			NodeSelector: map[string]string{"disk": "ssd"},
+			Tolerations:  []v1.Toleration{{Key: "gpu", Operator: v1.TolerationOpExists}},
+		}, true, []string{"a", "c", "d"}},
+	}
+
+	for _, test := range tests {
+		output := affinityNodeDomains(nodes, zone, v1.Pod{Spec: test.spec}, test.checkPod)
+		if strings.Join(output, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%s: output %v not equal to expected %v", test.name, output, test.expected)
+		}
+	}
+}
@@ -574,6 +574,18 @@ func (b *RowBuilder) setVisibleColumns(info *BuilderInformation) {
 	}
 }
 
+// setInitContainerType sets the container type of an init container, sidecar containers are init
+// containers that keep running so get their own type
+func setInitContainerType(info *BuilderInformation, isSidecar bool) {
+	if isSidecar {
+		info.ContainerType = TypeIDSidecarContainer
+		info.TypeName = TypeNameSidecarContainer
+		return
+	}
+	info.ContainerType = TypeIDInitContainer
+	info.TypeName = TypeNameInitContainer
+}
+
 // PodLoop given a pod we loop over all containers adding to the table as we go
 //
 //	returns a copy of rows added and nil on success
@@ -603,56 +615,62 @@ func (b *RowBuilder) podLoop(loop Looper, info BuilderInformation, pod v1.Pod, i
 		return podRowsOut, nil
 	}
 
-	if b.ShowInitContainers {
-		log.Debug("loop init Container")
-		info.ContainerType = TypeIDInitContainer
-		info.TypeName = TypeNameInitContainer
-		if b.LoopStatus {
-			log.Debug("processing LoopStatus")
-			for _, container := range pod.Status.InitContainerStatuses {
-				// should the container be processed
-				log.Debug("processing -", container.Name)
-				if skipContainerName(b.CommonFlags, container.Name) {
-					continue
-				}
+	// sidecar containers are always shown, other init containers only when asked for
+	log.Debug("loop init Container")
+	sidecars := sidecarContainerNames(pod)
+	if b.LoopStatus {
+		log.Debug("processing LoopStatus")
+		for _, container := range pod.Status.InitContainerStatuses {
+			// should the container be processed
+			log.Debug("processing -", container.Name)
+			if !b.ShowInitContainers && !sidecars[container.Name] {
+				continue
+			}
+			if skipContainerName(b.CommonFlags, container.Name) {
+				continue
+			}
 
-				info.Name = container.Name
-				allRows, err := loop.BuildContainerStatus(container, info)
-				if err != nil {
-					return [][]Cell{}, err
-				}
-				for _, row := range allRows {
-					rowsOut := b.makeFullRow(&info, indentLevel, row)
-					if !b.matchShouldExclude(rowsOut) {
-						b.Table.AddRow(rowsOut...)
-					}
+			setInitContainerType(&info, sidecars[container.Name])
+			info.Name = container.Name
+			allRows, err := loop.BuildContainerStatus(container, info)
+			if err != nil {
+				return [][]Cell{}, err
+			}
+			for _, row := range allRows {
+				rowsOut := b.makeFullRow(&info, indentLevel, row)
+				if !b.matchShouldExclude(rowsOut) {
+					b.Table.AddRow(rowsOut...)
 				}
-				podRowsOut = append(podRowsOut, allRows...)
 			}
+			podRowsOut = append(podRowsOut, allRows...)
 		}
+	}
 
-		if b.LoopSpec {
-			log.Debug("processing LoopSpec")
-			for _, container := range pod.Spec.InitContainers {
-				// should the container be processed
-				log.Debug("processing -", container.Name)
-				if skipContainerName(b.CommonFlags, container.Name) {
-					continue
-				}
+	if b.LoopSpec {
+		log.Debug("processing LoopSpec")
+		for _, container := range pod.Spec.InitContainers {
+			// should the container be processed
+			log.Debug("processing -", container.Name)
+			if !b.ShowInitContainers && !sidecars[container.Name] {
+				continue
+			}
+			if skipContainerName(b.CommonFlags, container.Name) {
+				continue
+			}
 
-				info.Name = container.Name
-				allRows, err := loop.BuildContainerSpec(container, info)
-				if err != nil {
-					return [][]Cell{}, err
-				}
-				for _, row := range allRows {
-					rowsOut := b.makeFullRow(&info, indentLevel, row)
-					if !b.matchShouldExclude(rowsOut) {
-						b.Table.AddRow(rowsOut...)
-					}
+			setInitContainerType(&info, sidecars[container.Name])
+			info.Name = container.Name
+			allRows, err := loop.BuildContainerSpec(container, info)
+			if err != nil {
+				return [][]Cell{}, err
+			}
+			for _, row := range allRows {
+				rowsOut := b.makeFullRow(&info, indentLevel, row)
+				if !b.matchShouldExclude(rowsOut) {
+					b.Table.AddRow(rowsOut...)
 				}
-				podRowsOut = append(podRowsOut, allRows...)
 			}
+			podRowsOut = append(podRowsOut, allRows...)
 		}
 	}
 
@@ -2,6 +2,9 @@ package plugin
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
@@ -11,6 +14,12 @@ import (
 var capabilitiesShort = "Shows details of configured containers POSIX capabilities"
 
 var capabilitiesDescription = ` View POSIX Capabilities that have been applied to the running containers.
+
+Use --effective to show the final set of capabilities the container runs with, this starts with the
+default set given to containers by the runtime (containerd by default, use --default-caps to change
+it) then applies the add and drop lists in the same order as the runtime, privileged containers are
+given all capabilities. ADDED-BEYOND-DEFAULT lists the capabilities that are not in the default set
+and RISK lists any dangerous capabilities the container holds.
 `
 
 var capabilitiesExample = `  # List container capabilities from pods
@@ -38,7 +47,13 @@ var capabilitiesExample = `  # List container capabilities from pods
   %[1]s capabilities -l app=web
 
   # List container capabilities info from all pods where the pod label app is either web or mail
-  %[1]s capabilities -l "app in (web,mail)"`
+  %[1]s capabilities -l "app in (web,mail)"
+
+  # List the effective capabilities of each container and any risky capabilities they hold
+  %[1]s capabilities --effective
+
+  # List the effective capabilities using a custom runtime default set
+  %[1]s capabilities --effective --default-caps CHOWN,SETUID,SETGID,NET_BIND_SERVICE`
 
 // list details of configured liveness readiness and startup capabilities
 func Capabilities(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
@@ -71,6 +86,18 @@ func Capabilities(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags,
 	builder.Table = &table
 	builder.ShowTreeView = commonFlagList.showTreeView
 
+	if cmd.Flag("effective").Value.String() == "true" {
+		log.Debug("loopinfo.ShowEffective = true")
+		loopinfo.ShowEffective = true
+	}
+
+	for _, capability := range strings.Split(cmd.Flag("default-caps").Value.String(), ",") {
+		if len(capability) > 0 {
+			loopinfo.DefaultCaps = append(loopinfo.DefaultCaps, normaliseCapability(capability))
+		}
+	}
+	log.Debug("loopinfo.DefaultCaps =", loopinfo.DefaultCaps)
+
 	if err := builder.Build(&loopinfo); err != nil {
 		return err
 	}
@@ -84,12 +111,36 @@ func Capabilities(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags,
 
 }
 
+// capabilitiesAll lists every linux capability, privileged containers are given all of them
+var capabilitiesAll = []string{
+	"CHOWN", "DAC_OVERRIDE", "DAC_READ_SEARCH", "FOWNER", "FSETID", "KILL", "SETGID", "SETUID",
+	"SETPCAP", "LINUX_IMMUTABLE", "NET_BIND_SERVICE", "NET_BROADCAST", "NET_ADMIN", "NET_RAW",
+	"IPC_LOCK", "IPC_OWNER", "SYS_MODULE", "SYS_RAWIO", "SYS_CHROOT", "SYS_PTRACE", "SYS_PACCT",
+	"SYS_ADMIN", "SYS_BOOT", "SYS_NICE", "SYS_RESOURCE", "SYS_TIME", "SYS_TTY_CONFIG", "MKNOD",
+	"LEASE", "AUDIT_WRITE", "AUDIT_CONTROL", "SETFCAP", "MAC_OVERRIDE", "MAC_ADMIN", "SYSLOG",
+	"WAKE_ALARM", "BLOCK_SUSPEND", "AUDIT_READ", "PERFMON", "BPF", "CHECKPOINT_RESTORE",
+}
+
+// capabilitiesContainerdDefault is the default set of capabilities containerd gives to containers
+var capabilitiesContainerdDefault = []string{
+	"CHOWN", "DAC_OVERRIDE", "FSETID", "FOWNER", "MKNOD", "NET_RAW", "SETGID", "SETUID", "SETFCAP",
+	"SETPCAP", "NET_BIND_SERVICE", "SYS_CHROOT", "KILL", "AUDIT_WRITE",
+}
+
+// capabilitiesRisky lists capabilities that allow a container to escape or attack its host or neighbours
+var capabilitiesRisky = []string{
+	"SYS_ADMIN", "NET_RAW", "SYS_PTRACE", "NET_ADMIN", "SYS_MODULE", "SYS_RAWIO", "DAC_READ_SEARCH",
+	"BPF", "PERFMON", "SYS_BOOT", "MAC_ADMIN", "MAC_OVERRIDE",
+}
+
 type capabilities struct {
+	ShowEffective bool
+	DefaultCaps   []string
 }
 
 func (s *capabilities) Headers() []string {
 	return []string{
-		"ADD", "DROP",
+		"ADD", "DROP", "EFFECTIVE", "ADDED-BEYOND-DEFAULT", "RISK",
 	}
 }
 
@@ -98,13 +149,19 @@ func (s *capabilities) BuildContainerStatus(container v1.ContainerStatus, info B
 }
 
 func (s *capabilities) HideColumns(info BuilderInformation) []int {
-	return []int{}
+	var hideColumns []int
+
+	if !s.ShowEffective {
+		hideColumns = append(hideColumns, 2, 3, 4)
+	}
+
+	return hideColumns
 }
 
 func (s *capabilities) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	out := []Cell{
-		NewCellText(""),
-		NewCellText(""),
+	out := make([]Cell, len(s.Headers()))
+	for i := range out {
+		out[i] = NewCellText("")
 	}
 	return out, nil
 }
@@ -152,9 +209,112 @@ func (s *capabilities) capabilitiesBuildRow(securityContext *v1.SecurityContext,
 		NewCellText(capDrop),
 	)
 
+	cellList = append(cellList, s.effectiveBuildCells(securityContext)...)
+
 	return cellList
 }
 
+// effectiveBuildCells returns the EFFECTIVE, ADDED-BEYOND-DEFAULT and RISK cells
+func (s *capabilities) effectiveBuildCells(securityContext *v1.SecurityContext) []Cell {
+	if !s.ShowEffective {
+		return []Cell{{}, {}, {}}
+	}
+
+	effective := effectiveCapabilities(s.DefaultCaps, securityContext)
+
+	var added []string
+	var risky []string
+	for _, capability := range effective {
+		if !slices.Contains(s.DefaultCaps, capability) {
+			added = append(added, capability)
+		}
+		if slices.Contains(capabilitiesRisky, capability) {
+			risky = append(risky, capability)
+		}
+	}
+
+	riskCell := NewCellColourText(colourOk, "")
+	if len(risky) > 0 {
+		riskCell = NewCellColourText(colourBad, strings.Join(risky, ","))
+	}
+
+	effectiveText := strings.Join(effective, ",")
+	if len(effective) == len(capabilitiesAll) {
+		effectiveText = "ALL"
+	}
+
+	return []Cell{
+		NewCellText(effectiveText),
+		NewCellText(strings.Join(added, ",")),
+		riskCell,
+	}
+}
+
+// effectiveCapabilities applies the containers add and drop lists to the default set using the same
+// order as the container runtime: add ALL, drop ALL, add the named capabilities then drop the named
+// capabilities, privileged containers always get every capability
+func effectiveCapabilities(defaultCaps []string, securityContext *v1.SecurityContext) []string {
+	current := make(map[string]bool)
+	for _, capability := range defaultCaps {
+		current[normaliseCapability(capability)] = true
+	}
+
+	if securityContext != nil {
+		if securityContext.Privileged != nil && *securityContext.Privileged {
+			return capabilitiesAll
+		}
+
+		if caps := securityContext.Capabilities; caps != nil {
+			for _, capability := range caps.Add {
+				if normaliseCapability(string(capability)) == "ALL" {
+					for _, name := range capabilitiesAll {
+						current[name] = true
+					}
+				}
+			}
+
+			for _, capability := range caps.Drop {
+				if normaliseCapability(string(capability)) == "ALL" {
+					current = make(map[string]bool)
+				}
+			}
+
+			for _, capability := range caps.Add {
+				if name := normaliseCapability(string(capability)); name != "ALL" {
+					current[name] = true
+				}
+			}
+
+			for _, capability := range caps.Drop {
+				if name := normaliseCapability(string(capability)); name != "ALL" {
+					delete(current, name)
+				}
+			}
+		}
+	}
+
+	// keep the output in a stable order, unknown capabilities are added to the end
+	var effective []string
+	for _, name := range capabilitiesAll {
+		if current[name] {
+			effective = append(effective, name)
+			delete(current, name)
+		}
+	}
+	var unknown []string
+	for name := range current {
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+
+	return append(effective, unknown...)
+}
+
+// normaliseCapability converts a capability name to upper case without the CAP_ prefix
+func normaliseCapability(name string) string {
+	return strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "CAP_")
+}
+
 func (s *capabilities) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
 	return [][]Cell{}, nil
 }
@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// *****************
+// effectiveCapabilities
+// *****************
+
+type effectiveCapabilitiesTest struct {
+	arg1     *v1.SecurityContext
+	expected string
+}
+
+var capPrivileged = true
+
+var effectiveCapabilitiesTests = []effectiveCapabilitiesTest{
+	{nil, "CHOWN,DAC_OVERRIDE,FOWNER,FSETID,KILL,SETGID,SETUID,SETPCAP,NET_BIND_SERVICE,NET_RAW,SYS_CHROOT,MKNOD,AUDIT_WRITE,SETFCAP"},
+	{&v1.SecurityContext{Capabilities: &v1.Capabilities{Drop: []v1.Capability{"ALL"}}}, ""},
+	{&v1.SecurityContext{Capabilities: &v1.Capabilities{Drop: []v1.Capability{"ALL"}, Add: []v1.Capability{"NET_BIND_SERVICE"}}}, "NET_BIND_SERVICE"},
+	{&v1.SecurityContext{Capabilities: &v1.Capabilities{Drop: []v1.Capability{"net_raw", "CAP_MKNOD", "KILL", "CHOWN", "SETUID", "SETGID", "FOWNER"}}}, "DAC_OVERRIDE,FSETID,SETPCAP,NET_BIND_SERVICE,SYS_CHROOT,AUDIT_WRITE,SETFCAP"},
+	{&v1.SecurityContext{Capabilities: &v1.Capabilities{Drop: []v1.Capability{"ALL"}, Add: []v1.Capability{"SYS_ADMIN", "SYS_PTRACE"}}}, "SYS_PTRACE,SYS_ADMIN"},
+	{&v1.SecurityContext{Capabilities: &v1.Capabilities{Add: []v1.Capability{"ALL"}, Drop: []v1.Capability{"ALL"}}}, ""},
+	{&v1.SecurityContext{Privileged: &capPrivileged, Capabilities: &v1.Capabilities{Drop: []v1.Capability{"ALL"}}}, strings.Join(capabilitiesAll, ",")},
+}
+
+func TestEffectiveCapabilities(t *testing.T) {
+
+	for _, test := range effectiveCapabilitiesTests {
+		output := strings.Join(effectiveCapabilities(capabilitiesContainerdDefault, test.arg1), ",")
+		if output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"bytes"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,7 +15,7 @@ var commandsDescription = ` Prints command and arguments used to start each cont
 containers can be selected by name.  If no name is specified the container commands of all pods
 in the current namespace are shown.
 
-The T column in the table output denotes S for Standard and I for init containers`
+The T column in the table output denotes S for Standard, I for init and K for sidecar containers`
 
 var commandsExample = `  # List containers command info from pods
   %[1]s command
@@ -41,11 +42,17 @@ var commandsExample = `  # List containers command info from pods
   %[1]s command -l app=web
 
   # List container command info from all pods where the pod label app is either web or mail
-  %[1]s command -l "app in (web,mail)"`
+  %[1]s command -l "app in (web,mail)"
+
+  # List container commands along with the command line after $(VAR) references have been
+  # expanded using the containers environment
+  %[1]s command --expand`
 
 type commandLine struct {
-	cmd  []string
-	args []string
+	cmd     []string
+	args    []string
+	env     []v1.EnvVar
+	envFrom []v1.EnvFromSource
 }
 
 func Commands(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
@@ -72,6 +79,22 @@ func Commands(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 	builder.Connection = &connect
 	builder.SetFlagsFrom(commonFlagList)
 
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+
+	// we need the connection details so we can translate configmap values, this is only
+	// possible when we are reading live data
+	if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
+		loopinfo.Connection = &connect
+	}
+
+	if cmd.Flag("expand").Value.String() == "true" {
+		log.Debug("loopinfo.ShowExpanded = true")
+		loopinfo.ShowExpanded = true
+	}
+
 	table := Table{}
 	table.ColourOutput = commonFlagList.outputAsColour
 	table.CustomColours = commonFlagList.useTheseColours
@@ -92,12 +115,17 @@ func Commands(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 
 }
 
+// commandsUnknownValue is shown in place of any variable whose value could not be read
+const commandsUnknownValue = "<unknown>"
+
 type commands struct {
+	Connection   *Connector
+	ShowExpanded bool
 }
 
 func (s *commands) Headers() []string {
 	return []string{
-		"COMMAND", "ARGUMENTS",
+		"COMMAND", "ARGUMENTS", "EXPANDED-COMMAND", "EXPANDED-ARGUMENTS",
 	}
 }
 
@@ -106,45 +134,224 @@ func (s *commands) BuildContainerStatus(container v1.ContainerStatus, info Build
 }
 
 func (s *commands) HideColumns(info BuilderInformation) []int {
+	if !s.ShowExpanded {
+		return []int{2, 3}
+	}
 	return []int{}
 }
 
 func (s *commands) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
 	out := []Cell{
 		NewCellText(""),
-		NewCellText("")}
+		NewCellText(""),
+		NewCellText(""),
+		NewCellText(""),
+	}
 	return out, nil
 }
 
 func (s *commands) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
 	cmdLine := commandLine{
-		cmd:  container.Command,
-		args: container.Args,
+		cmd:     container.Command,
+		args:    container.Args,
+		env:     container.Env,
+		envFrom: container.EnvFrom,
 	}
-	out := make([][]Cell, 1)
-	out[0] = s.commandsBuildRow(cmdLine, info)
-	return out, nil
+	row, err := s.commandsBuildRow(cmdLine, info)
+	if err != nil {
+		return [][]Cell{}, err
+	}
+	return [][]Cell{row}, nil
 }
 
 func (s *commands) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
 	cmdLine := commandLine{
-		cmd:  container.Command,
-		args: container.Args,
+		cmd:     container.Command,
+		args:    container.Args,
+		env:     container.Env,
+		envFrom: container.EnvFrom,
 	}
-	out := make([][]Cell, 1)
-	out[0] = s.commandsBuildRow(cmdLine, info)
-	return out, nil
+	row, err := s.commandsBuildRow(cmdLine, info)
+	if err != nil {
+		return [][]Cell{}, err
+	}
+	return [][]Cell{row}, nil
 }
 
-func (s *commands) commandsBuildRow(cmdLine commandLine, info BuilderInformation) []Cell {
+func (s *commands) commandsBuildRow(cmdLine commandLine, info BuilderInformation) ([]Cell, error) {
 	var cellList []Cell
+	var expandedCmd []string
+	var expandedArgs []string
+
+	if s.ShowExpanded {
+		envMap, unknownSource, err := s.buildEnvMap(info.Data.pod, cmdLine.envFrom, cmdLine.env)
+		if err != nil {
+			return cellList, err
+		}
+		mapping := commandsMappingFor(envMap, unknownSource)
+
+		for _, v := range cmdLine.cmd {
+			expandedCmd = append(expandedCmd, expandVariables(v, mapping))
+		}
+
+		for _, v := range cmdLine.args {
+			expandedArgs = append(expandedArgs, expandVariables(v, mapping))
+		}
+	}
 
 	cellList = append(cellList,
 		NewCellText(strings.Join(cmdLine.cmd, " ")),
 		NewCellText(strings.Join(cmdLine.args, " ")),
+		NewCellText(strings.Join(expandedCmd, " ")),
+		NewCellText(strings.Join(expandedArgs, " ")),
 	)
 
-	return cellList
+	return cellList, nil
+}
+
+// buildEnvMap creates the effective environment of a container the same way the kubelet does, envFrom
+// sources are added first then each env entry is added in order with any $(VAR) references in the
+// value expanded using the variables defined before it. Secrets are never read so any variable that
+// comes from a secret, or a configmap that could not be read, is set to <unknown>. True is returned
+// when a whole envFrom source could not be read as any undefined variable may come from it
+func (s *commands) buildEnvMap(pod v1.Pod, envFrom []v1.EnvFromSource, envList []v1.EnvVar) (map[string]string, bool, error) {
+	envMap := make(map[string]string)
+	unknownSource := false
+
+	for _, source := range envFrom {
+		if source.ConfigMapRef == nil || s.Connection == nil {
+			// secrets are never read and configmaps can only be read from a live cluster
+			unknownSource = true
+			continue
+		}
+
+		configMap, found, err := s.Connection.GetConfigMapData(source.ConfigMapRef.Name, pod.Namespace)
+		if err != nil {
+			return envMap, unknownSource, err
+		}
+		if !found {
+			// a missing optional configmap is skipped by the kubelet
+			if !isOptional(source.ConfigMapRef.Optional) {
+				unknownSource = true
+			}
+			continue
+		}
+
+		for key, value := range configMap {
+			envMap[source.Prefix+key] = value
+		}
+	}
+
+	for _, env := range envList {
+		if env.ValueFrom == nil {
+			envMap[env.Name] = expandVariables(env.Value, commandsMappingFor(envMap, unknownSource))
+			continue
+		}
+
+		if env.ValueFrom.ConfigMapKeyRef != nil && s.Connection != nil {
+			configMap, _, err := s.Connection.GetConfigMapData(env.ValueFrom.ConfigMapKeyRef.Name, pod.Namespace)
+			if err != nil {
+				return envMap, unknownSource, err
+			}
+			if value, ok := configMap[env.ValueFrom.ConfigMapKeyRef.Key]; ok {
+				envMap[env.Name] = value
+				continue
+			}
+			if isOptional(env.ValueFrom.ConfigMapKeyRef.Optional) {
+				// a missing optional key is never set by the kubelet
+				continue
+			}
+		}
+
+		if env.ValueFrom.FieldRef != nil {
+			if value, ok := resolveFieldRef(pod, env.ValueFrom.FieldRef.FieldPath); ok {
+				envMap[env.Name] = value
+				continue
+			}
+		}
+
+		envMap[env.Name] = commandsUnknownValue
+	}
+
+	return envMap, unknownSource, nil
+}
+
+// isOptional returns the value of an optional flag, unset flags are not optional
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+// commandsMappingFor returns the mapping function used to expand variables, when unknownSource is true
+// undefined variables are shown as <unknown> as they could have been set by an envFrom source
+func commandsMappingFor(envMap map[string]string, unknownSource bool) func(string) string {
+	if !unknownSource {
+		return mappingFuncFor(envMap)
+	}
+	return func(name string) string {
+		if value, ok := envMap[name]; ok {
+			return value
+		}
+		return commandsUnknownValue
+	}
+}
+
+// mappingFuncFor returns a mapping function for use with expandVariables, undefined variables are
+// returned unchanged in their original $(VAR) form
+func mappingFuncFor(envMap map[string]string) func(string) string {
+	return func(name string) string {
+		if value, ok := envMap[name]; ok {
+			return value
+		}
+		return "$(" + name + ")"
+	}
+}
+
+// expandVariables replaces $(VAR) references in input with the value returned by mapping, this follows
+// the kubelet dependent variable rules: $$ is an escaped $ and is reduced to a single $, invalid or
+// unclosed references are left as is
+func expandVariables(input string, mapping func(string) string) string {
+	var buf bytes.Buffer
+	checkpoint := 0
+
+	for cursor := 0; cursor < len(input); cursor++ {
+		if input[cursor] == '$' && cursor+1 < len(input) {
+			// copy the text between the last checkpoint and the current operator
+			buf.WriteString(input[checkpoint:cursor])
+
+			read, isVar, advance := readVariableName(input[cursor+1:])
+			if isVar {
+				buf.WriteString(mapping(read))
+			} else {
+				buf.WriteString(read)
+			}
+
+			cursor += advance
+			checkpoint = cursor + 1
+		}
+	}
+
+	return buf.String() + input[checkpoint:]
+}
+
+// readVariableName reads the variable name that follows a $ operator, returns the string that was read,
+// true if it was a variable reference and the number of bytes that need to be skipped
+func readVariableName(input string) (string, bool, int) {
+	switch input[0] {
+	case '$':
+		// escaped operator
+		return input[0:1], false, 1
+	case '(':
+		for i := 1; i < len(input); i++ {
+			if input[i] == ')' {
+				return input[1:i], true, i + 1
+			}
+		}
+		// no closing bracket so we return the opening chars as is
+		return "$(", false, 1
+	default:
+		// not a variable reference, return the operator along with the char that follows
+		return "$" + string(input[0]), false, 1
+	}
 }
 
 func (s *commands) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
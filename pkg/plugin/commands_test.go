@@ -0,0 +1,117 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// *****************
+// expandVariables
+// *****************
+type expandVariablesTest struct {
+	arg1     string
+	expected string
+}
+
+var expandVariablesEnv = map[string]string{
+	"POD_IP":    "10.0.0.1",
+	"JAVA_OPTS": "-Xmx512m",
+	"EMPTY":     "",
+}
+
+var expandVariablesTests = []expandVariablesTest{
+	{"", ""},
+	{"plain text", "plain text"},
+	{"$(POD_IP)", "10.0.0.1"},
+	{"--bind=$(POD_IP):8080", "--bind=10.0.0.1:8080"},
+	{"java $(JAVA_OPTS) -jar app.jar", "java -Xmx512m -jar app.jar"},
+	{"$(POD_IP)$(JAVA_OPTS)", "10.0.0.1-Xmx512m"},
+	{"$(EMPTY)value", "value"},
+	// undefined references are left as is
+	{"$(UNDEFINED)", "$(UNDEFINED)"},
+	// escaped references are not expanded
+	{"$$(POD_IP)", "$(POD_IP)"},
+	{"$$$(POD_IP)", "$10.0.0.1"},
+	{"$$", "$"},
+	// invalid and unclosed references are left as is
+	{"$POD_IP", "$POD_IP"},
+	{"$(POD_IP", "$(POD_IP"},
+	{"value$", "value$"},
+	{"$()", "$()"},
+}
+
+func TestExpandVariables(t *testing.T) {
+	mapping := mappingFuncFor(expandVariablesEnv)
+
+	for _, test := range expandVariablesTests {
+		output := expandVariables(test.arg1, mapping)
+		if output != test.expected {
+			t.Errorf("Output %s not equal to expected %s, using input %s", output, test.expected, test.arg1)
+		}
+	}
+}
+
+// *****************
+// buildEnvMap
+// *****************
+
+func TestBuildEnvMap(t *testing.T) {
+	s := commands{}
+	optional := true
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+
+	envList := []v1.EnvVar{
+		{Name: "PORT", Value: "8080"},
+		{Name: "POD_NAME", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+		{Name: "PASSWORD", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{Key: "password"}}},
+		{Name: "MODE", ValueFrom: &v1.EnvVarSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{Key: "mode"}}},
+		{Name: "LISTEN", Value: "$(POD_NAME):$(PORT) $(MISSING)"},
+	}
+
+	tests := []struct {
+		name     string
+		envFrom  []v1.EnvFromSource
+		expected map[string]string
+		unknown  bool
+	}{
+		{"without envFrom", nil, map[string]string{
+			"PORT":     "8080",
+			"POD_NAME": "web-1",
+			"PASSWORD": "<unknown>",
+			"MODE":     "<unknown>",
+			"LISTEN":   "web-1:8080 $(MISSING)",
+		}, false},
+		{"unreadable envFrom", []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{}}}, map[string]string{
+			"PORT":     "8080",
+			"POD_NAME": "web-1",
+			"PASSWORD": "<unknown>",
+			"MODE":     "<unknown>",
+			"LISTEN":   "web-1:8080 <unknown>",
+		}, true},
+	}
+
+	for _, test := range tests {
+		envMap, unknown, err := s.buildEnvMap(pod, test.envFrom, envList)
+		if err != nil {
+			t.Errorf("%s: output error %s not equal to expected nil", test.name, err)
+			continue
+		}
+		if unknown != test.unknown {
+			t.Errorf("%s: output unknown source %t not equal to expected %t", test.name, unknown, test.unknown)
+		}
+		for key, expected := range test.expected {
+			if envMap[key] != expected {
+				t.Errorf("%s: output %s=%s not equal to expected %s", test.name, key, envMap[key], expected)
+			}
+		}
+	}
+
+	// configmaps cant be read from a file so even optional references are unknown
+	optionalEnv := []v1.EnvVar{{Name: "MODE", ValueFrom: &v1.EnvVarSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{Key: "mode", Optional: &optional}}}}
+	envMap, _, _ := s.buildEnvMap(pod, nil, optionalEnv)
+	if value, ok := envMap["MODE"]; !ok || value != "<unknown>" {
+		t.Errorf("Output MODE=%s not equal to expected <unknown> when the configmap cant be read", value)
+	}
+}
@@ -0,0 +1,287 @@
+package plugin
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	duration "k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var conditionsShort = "List the conditions and readiness gates of each pod"
+
+var conditionsDescription = ` Prints every condition reported in the pods status along with its status, reason, message and
+the time of the last transition. Readiness gates declared in the pod spec are marked in the GATE
+column, a readiness gate whose condition has not been set yet is shown with the status Missing as
+the pod will not become ready until a controller sets it.
+
+When using the tree view the PROBLEMS column on each owner lists the number of pods that have each
+condition set to False or missing, DisruptionTarget is the exception and is counted when True as the
+pod is about to be removed.`
+
+var conditionsExample = `  # List the conditions of all pods in the current namespace
+  %[1]s conditions
+
+  # List the conditions of pods output in JSON format
+  %[1]s conditions -o json
+
+  # List the conditions of a single pod
+  %[1]s conditions my-pod-4jh36
+
+  # List only the conditions that are not true
+  %[1]s conditions --match 'STATUS!=True'
+
+  # Show how many pods have a problem with each condition grouped by owner
+  %[1]s conditions --tree
+
+  # List the conditions of all pods where the pod label app is either web or mail
+  %[1]s conditions -l "app in (web,mail)"`
+
+// known pod conditions in the order they normally occur, used to order the summary
+var conditionsOrder = []string{
+	string(v1.PodScheduled),
+	string(v1.PodReadyToStartContainers),
+	string(v1.PodInitialized),
+	string(v1.ContainersReady),
+	string(v1.PodReady),
+	string(v1.DisruptionTarget),
+}
+
+const conditionsStatusMissing = "Missing"
+
+func Conditions(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Conditions"}
+	log.Debug("Start")
+
+	loopinfo := conditions{}
+	builder := RowBuilder{}
+	builder.DontListContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	table := Table{}
+	table.ColourOutput = commonFlagList.outputAsColour
+	table.CustomColours = commonFlagList.useTheseColours
+
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type conditions struct {
+}
+
+func (s *conditions) Headers() []string {
+	return []string{
+		"CONDITION", "STATUS", "REASON", "GATE", "TIMESTAMP", "AGE", "MESSAGE", "PROBLEMS",
+	}
+}
+
+func (s *conditions) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *conditions) BuildEphemeralContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *conditions) HideColumns(info BuilderInformation) []int {
+	// the summary is only filled in on the tree branches
+	if !info.TreeView {
+		return []int{7}
+	}
+	return []int{}
+}
+
+// BuildBranch counts the conditions that are a problem (see conditionsIsProblem), pod rows count there own conditions and
+// owner rows add up the counts from each child row
+func (s *conditions) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, len(s.Headers()))
+	for i := range rowOut {
+		rowOut[i] = NewCellText("")
+	}
+
+	counts := make(map[string]int64)
+	for _, r := range rows {
+		if len(r[0].text) > 0 {
+			// a condition row
+			if conditionsIsProblem(r[0].text, r[1].text) {
+				counts[r[0].text]++
+			}
+			continue
+		}
+
+		// a branch row, add the summary from the child
+		for name, count := range parseCountList(r[7].text) {
+			counts[name] += count
+		}
+	}
+
+	if info.TypeName == TypeNamePod {
+		for _, condition := range info.Data.pod.Status.Conditions {
+			if condition.Type == v1.PodReady {
+				rowOut[1] = conditionsStatusCell(condition.Type, condition.Status)
+			}
+		}
+	}
+
+	rowOut[7] = conditionsSummaryCell(counts)
+	return rowOut, nil
+}
+
+func (s *conditions) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *conditions) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *conditions) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
+	return conditionsBuildRows(pod), nil
+}
+
+// conditionsBuildRows returns a row for each condition in the pods status followed by a row for
+// each readiness gate that has no matching condition
+func conditionsBuildRows(pod v1.Pod) [][]Cell {
+	out := [][]Cell{}
+
+	gates := make(map[v1.PodConditionType]bool)
+	for _, gate := range pod.Spec.ReadinessGates {
+		gates[gate.ConditionType] = false
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		_, isGate := gates[condition.Type]
+		if isGate {
+			gates[condition.Type] = true
+		}
+
+		timestamp := ""
+		age := ""
+		if !condition.LastTransitionTime.IsZero() {
+			timestamp = condition.LastTransitionTime.Format(timestampFormat)
+			age = duration.HumanDuration(time.Since(condition.LastTransitionTime.Time))
+		}
+
+		out = append(out, []Cell{
+			NewCellText(string(condition.Type)),
+			conditionsStatusCell(condition.Type, condition.Status),
+			NewCellText(condition.Reason),
+			NewCellText(fmt.Sprintf("%t", isGate)),
+			NewCellText(timestamp),
+			NewCellText(age),
+			NewCellText(condition.Message),
+			NewCellText(""),
+		})
+	}
+
+	// readiness gates are listed in spec order so the output is stable
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gates[gate.ConditionType] {
+			continue
+		}
+		gates[gate.ConditionType] = true
+
+		out = append(out, []Cell{
+			NewCellText(string(gate.ConditionType)),
+			NewCellColourText(colourBad, conditionsStatusMissing),
+			NewCellText(""),
+			NewCellText("true"),
+			NewCellText(""),
+			NewCellText(""),
+			NewCellText("condition has not been set, the pod will not become ready"),
+			NewCellText(""),
+		})
+	}
+
+	return out
+}
+
+// conditionsIsProblem returns true when the condition status needs attention, this is False or
+// missing for every condition apart from DisruptionTarget which is only a problem when True
+func conditionsIsProblem(conditionType string, status string) bool {
+	if conditionType == string(v1.DisruptionTarget) {
+		return status == string(v1.ConditionTrue)
+	}
+	return status == string(v1.ConditionFalse) || status == conditionsStatusMissing
+}
+
+// conditionsStatusCell colours the status, DisruptionTarget is the only condition where true is bad
+func conditionsStatusCell(conditionType v1.PodConditionType, status v1.ConditionStatus) Cell {
+	switch status {
+	case v1.ConditionTrue:
+		if conditionType == v1.DisruptionTarget {
+			return NewCellColourText(colourWarn, string(status))
+		}
+		return NewCellColourText(colourOk, string(status))
+	case v1.ConditionFalse:
+		if conditionType == v1.DisruptionTarget {
+			return NewCellColourText(colourOk, string(status))
+		}
+		return NewCellColourText(colourBad, string(status))
+	}
+
+	return NewCellColourText(colourWarn, string(status))
+}
+
+// conditionsSummaryCell formats the counts as a list of condition:count, known conditions are
+// listed first in the order they occur followed by any custom conditions sorted by name
+func conditionsSummaryCell(counts map[string]int64) Cell {
+	var total int64
+	list := []string{}
+
+	for _, name := range conditionsOrder {
+		if counts[name] > 0 {
+			list = append(list, fmt.Sprintf("%s:%d", name, counts[name]))
+			total += counts[name]
+		}
+	}
+
+	custom := []string{}
+	for name, count := range counts {
+		if count > 0 && !slices.Contains(conditionsOrder, name) {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	for _, name := range custom {
+		list = append(list, fmt.Sprintf("%s:%d", name, counts[name]))
+		total += counts[name]
+	}
+
+	if total == 0 {
+		return NewCellInt("", 0)
+	}
+
+	return NewCellColourInt(colourBad, strings.Join(list, ","), total)
+}
@@ -0,0 +1,98 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// *****************
+// conditionsBuildRows
+// *****************
+
+func TestConditionsBuildRowsReadinessGates(t *testing.T) {
+	pod := v1.Pod{
+		Spec: v1.PodSpec{
+			ReadinessGates: []v1.PodReadinessGate{
+				{ConditionType: "example.com/set"},
+				{ConditionType: "example.com/missing"},
+			},
+		},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionFalse},
+				{Type: "example.com/set", Status: v1.ConditionTrue},
+			},
+		},
+	}
+
+	expected := [][]string{
+		{"Ready", "False", "false"},
+		{"example.com/set", "True", "true"},
+		{"example.com/missing", conditionsStatusMissing, "true"},
+	}
+
+	output := conditionsBuildRows(pod)
+	if len(output) != len(expected) {
+		t.Fatalf("Output has %d rows expected %d", len(output), len(expected))
+	}
+
+	for i, row := range expected {
+		if output[i][0].text != row[0] || output[i][1].text != row[1] || output[i][3].text != row[2] {
+			t.Errorf("Output row %d %s %s %s not equal to expected %v", i, output[i][0].text, output[i][1].text, output[i][3].text, row)
+		}
+	}
+}
+
+// *****************
+// BuildBranch
+// *****************
+
+func TestConditionsBuildBranch(t *testing.T) {
+	s := conditions{}
+
+	podRows := conditionsBuildRows(v1.Pod{
+		Spec: v1.PodSpec{
+			ReadinessGates: []v1.PodReadinessGate{{ConditionType: "example.com/gate"}},
+		},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue},
+				{Type: v1.ContainersReady, Status: v1.ConditionFalse},
+				{Type: v1.PodReady, Status: v1.ConditionFalse},
+				{Type: v1.DisruptionTarget, Status: v1.ConditionFalse},
+			},
+		},
+	})
+
+	if headers := s.Headers(); headers[7] != "PROBLEMS" {
+		t.Errorf("Output header %s not equal to expected PROBLEMS", headers[7])
+	}
+
+	podBranch, _ := s.BuildBranch(BuilderInformation{TypeName: TypeNamePod}, podRows)
+	if podBranch[7].text != "ContainersReady:1,Ready:1,example.com/gate:1" {
+		t.Errorf("Pod problems %s not equal to expected", podBranch[7].text)
+	}
+
+	otherPod := []Cell{NewCellText(""), NewCellText(""), NewCellText(""), NewCellText(""), NewCellText(""), NewCellText(""), NewCellText(""), NewCellText("Ready:2")}
+	ownerBranch, _ := s.BuildBranch(BuilderInformation{TypeName: "ReplicaSet"}, [][]Cell{podBranch, otherPod})
+	if ownerBranch[7].text != "ContainersReady:1,Ready:3,example.com/gate:1" {
+		t.Errorf("Owner problems %s not equal to expected", ownerBranch[7].text)
+	}
+	if ownerBranch[7].number != 5 {
+		t.Errorf("Owner total %d not equal to expected 5", ownerBranch[7].number)
+	}
+	// a pod that is about to be removed is counted under DisruptionTarget
+	evicted := conditionsBuildRows(v1.Pod{
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{
+				{Type: v1.DisruptionTarget, Status: v1.ConditionTrue},
+				{Type: v1.PodReady, Status: v1.ConditionTrue},
+			},
+		},
+	})
+	evictedBranch, _ := s.BuildBranch(BuilderInformation{TypeName: TypeNamePod}, evicted)
+	if evictedBranch[7].text != "DisruptionTarget:1" {
+		t.Errorf("Evicted pod problems %s not equal to expected DisruptionTarget:1", evictedBranch[7].text)
+	}
+}
@@ -1,6 +1,8 @@
 package plugin
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -12,7 +14,7 @@ var environmentDescription = ` Print the the environment variables used in runni
 and containers can be selected by name. If no name is specified the environment details of all pods in
 the current namespace are shown.
 
-The T column in the table output denotes S for Standard and I for init containers`
+The T column in the table output denotes S for Standard, I for init and K for sidecar containers`
 
 var environmentExample = `  # List containers env info from pods
   %[1]s env
@@ -205,3 +207,43 @@ func (s *environment) buildEnvFromEphemeral(container v1.EphemeralContainer) []v
 func (s *environment) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
 	return [][]Cell{}, nil
 }
+
+// resolveFieldRef returns the value of the pod field selected by fieldPath, only the fields
+// supported by the downward api are translated, false is returned for anything else
+func resolveFieldRef(pod v1.Pod, fieldPath string) (string, bool) {
+	switch fieldPath {
+	case "metadata.name":
+		return pod.Name, true
+	case "metadata.namespace":
+		return pod.Namespace, true
+	case "metadata.uid":
+		return string(pod.UID), true
+	case "spec.nodeName":
+		return pod.Spec.NodeName, true
+	case "spec.serviceAccountName":
+		return pod.Spec.ServiceAccountName, true
+	case "status.hostIP":
+		return pod.Status.HostIP, true
+	case "status.podIP":
+		return pod.Status.PodIP, true
+	case "status.podIPs":
+		ipList := []string{}
+		for _, ip := range pod.Status.PodIPs {
+			ipList = append(ipList, ip.IP)
+		}
+		return strings.Join(ipList, ","), true
+	}
+
+	// labels and annotations are selected using the form metadata.labels['name']
+	if strings.HasPrefix(fieldPath, "metadata.labels['") && strings.HasSuffix(fieldPath, "']") {
+		name := strings.TrimSuffix(strings.TrimPrefix(fieldPath, "metadata.labels['"), "']")
+		return pod.Labels[name], true
+	}
+
+	if strings.HasPrefix(fieldPath, "metadata.annotations['") && strings.HasSuffix(fieldPath, "']") {
+		name := strings.TrimSuffix(strings.TrimPrefix(fieldPath, "metadata.annotations['"), "']")
+		return pod.Annotations[name], true
+	}
+
+	return "", false
+}
@@ -0,0 +1,344 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	v1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+var evictionShort = "Rank pods in the order the kubelet evicts them when a node runs out of memory"
+
+var evictionDescription = ` Prints the position of each pod in the order the kubelet evicts pods from a node that is under
+memory pressure. Pods using more memory than they request are evicted first, then pods with the
+lowest priority and finally the pods using the most memory above their request. Pods without usage
+metrics are ranked before all other pods.
+
+Every running pod on the node is ranked, not just the selected pods, RANK shows the position of the
+pod on its node and NODE-PODS the number of pods ranked on that node. Requests are read from the pod
+spec and memory usage from the metrics server, when reading pods from a file there is no usage so
+only the requests, priority and QoS class are shown.`
+
+var evictionExample = `  # List the eviction rank of all pods in the current namespace
+  %[1]s eviction
+
+  # List the eviction rank of pods from all namespaces grouped by node
+  %[1]s eviction -A --node-tree
+
+  # List the pods that are first in line to be evicted on their node
+  %[1]s eviction -A --match 'RANK<=3'
+
+  # List the eviction rank of all pods where label app equals web
+  %[1]s eviction -l app=web`
+
+func Eviction(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Eviction"}
+	log.Debug("Start")
+
+	loopinfo := eviction{}
+	builder := RowBuilder{}
+	builder.DontListContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	// pods are ranked on each node so always show the node
+	commonFlagList.showNodeName = true
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	// pods can only be ranked when we are connected to a cluster
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+	if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
+		if err := connect.LoadMetricConfig(kubeFlags); err != nil {
+			return err
+		}
+		if err := connect.LoadAllMetricPods(); err != nil {
+			log.Tell(err)
+		}
+		loopinfo.Connection = &connect
+	}
+
+	if cmd.Flag("size") != nil {
+		if len(cmd.Flag("size").Value.String()) > 0 {
+			loopinfo.BytesAs = cmd.Flag("size").Value.String()
+		}
+	}
+
+	table := Table{}
+	table.ColourOutput = commonFlagList.outputAsColour
+	table.CustomColours = commonFlagList.useTheseColours
+
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	sortList := commonFlagList.sortList
+	if len(sortList) == 0 && !commonFlagList.showTreeView {
+		// list the pods on each node in the order they are evicted
+		sortList = []string{"RANK", "NODE"}
+	}
+	if err := table.SortByNames(sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type eviction struct {
+	Connection *Connector // nil when the pods are read from a file
+	BytesAs    string
+
+	usage    map[string]int64 // memory usage of each namespace/pod
+	ranks    map[string]int64 // eviction rank of each namespace/pod
+	nodeSize map[string]int64 // number of pods ranked on each node
+}
+
+// evictionCandidate holds the values the kubelet uses to rank a pod for eviction
+type evictionCandidate struct {
+	name     string // namespace/pod
+	priority int32
+	request  int64
+	used     int64
+	hasUsage bool
+}
+
+func (s *eviction) Headers() []string {
+	return []string{
+		"RANK", "NODE-PODS", "EXCEEDS-REQUEST", "PRIORITY", "MEM-REQUEST", "MEM-USED", "OVER-REQUEST", "QOS",
+	}
+}
+
+func (s *eviction) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *eviction) BuildEphemeralContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *eviction) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *eviction) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *eviction) HideColumns(info BuilderInformation) []int {
+	if s.Connection == nil {
+		// no ranking or usage when reading from a file
+		return []int{0, 1, 2, 5, 6}
+	}
+	return []int{}
+}
+
+// BuildBranch shows the pod row on the pod branch, other branches total the memory of the pods
+// below them and count the pods using more memory than they request
+func (s *eviction) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	if info.TypeName == TypeNamePod {
+		return s.podRow(info.Data.pod), nil
+	}
+
+	rowOut := make([]Cell, len(s.Headers()))
+	for i := range rowOut {
+		rowOut[i] = NewCellText("")
+	}
+
+	var exceeds, request, used, over int64
+	qosCounts := make(map[string]int64)
+	for _, r := range rows {
+		if r[2].text == "true" {
+			exceeds++
+		} else {
+			exceeds += r[2].number
+		}
+		request += r[4].number
+		used += r[5].number
+		over += r[6].number
+		addCountList(qosCounts, r[7].text)
+	}
+
+	rowOut[2] = NewCellInt(fmt.Sprintf("%d", exceeds), exceeds)
+	rowOut[4] = NewCellInt(memoryHumanReadable(request, s.BytesAs), request)
+	if s.Connection != nil {
+		rowOut[5] = NewCellInt(memoryHumanReadable(used, s.BytesAs), used)
+		rowOut[6] = NewCellInt(memoryHumanReadable(over, s.BytesAs), over)
+	}
+	rowOut[7] = NewCellText(statusFormatReasonCounts(qosCounts))
+
+	return rowOut, nil
+}
+
+func (s *eviction) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
+	if info.TreeView {
+		// the pod branch already shows the pod
+		return [][]Cell{}, nil
+	}
+	return [][]Cell{s.podRow(pod)}, nil
+}
+
+// podRow returns the eviction details of the pod
+func (s *eviction) podRow(pod v1.Pod) []Cell {
+	name := pod.Namespace + "/" + pod.Name
+	candidate := evictionNewCandidate(pod, s.podUsage())
+
+	rank := NewCellText("")
+	nodeSize := NewCellText("")
+	if s.Connection != nil && len(pod.Spec.NodeName) > 0 {
+		s.rankNode(pod.Spec.NodeName)
+		if value, ok := s.ranks[name]; ok {
+			rank = NewCellInt(fmt.Sprintf("%d", value), value)
+			nodeSize = NewCellInt(fmt.Sprintf("%d", s.nodeSize[pod.Spec.NodeName]), s.nodeSize[pod.Spec.NodeName])
+		}
+	}
+
+	exceeds := NewCellText("")
+	used := NewCellText("")
+	over := NewCellText("")
+	if candidate.hasUsage {
+		isOver := candidate.used > candidate.request
+		exceeds = NewCellColourText(evictionExceedsColour(isOver), fmt.Sprintf("%t", isOver))
+		used = NewCellInt(memoryHumanReadable(candidate.used, s.BytesAs), candidate.used)
+		overValue := candidate.used - candidate.request
+		over = NewCellInt(memoryHumanReadable(overValue, s.BytesAs), overValue)
+	}
+
+	return []Cell{
+		rank,
+		nodeSize,
+		exceeds,
+		NewCellInt(fmt.Sprintf("%d", candidate.priority), int64(candidate.priority)),
+		NewCellInt(memoryHumanReadable(candidate.request, s.BytesAs), candidate.request),
+		used,
+		over,
+		qosCell(pod),
+	}
+}
+
+// podUsage returns the memory usage of every pod, the metrics are only read once
+func (s *eviction) podUsage() map[string]int64 {
+	if s.usage == nil {
+		s.usage = map[string]int64{}
+		if s.Connection != nil {
+			s.usage = evictionPodUsage(s.Connection.GetAllMetricPods())
+		}
+	}
+	return s.usage
+}
+
+// rankNode ranks all running pods on the node, nodes that have already been ranked are skipped
+func (s *eviction) rankNode(nodeName string) {
+	if s.ranks == nil {
+		s.ranks = make(map[string]int64)
+		s.nodeSize = make(map[string]int64)
+	}
+	if _, ok := s.nodeSize[nodeName]; ok {
+		return
+	}
+
+	candidates := []evictionCandidate{}
+	for _, pod := range s.Connection.GetNodePods(nodeName) {
+		candidates = append(candidates, evictionNewCandidate(pod, s.podUsage()))
+	}
+
+	for name, rank := range evictionRank(candidates) {
+		s.ranks[name] = rank
+	}
+	s.nodeSize[nodeName] = int64(len(candidates))
+}
+
+// evictionNewCandidate reads the values used to rank the pod, the request is calculated the same
+// way as the scheduler so includes sidecars, pod level resources and the pod overhead
+func evictionNewCandidate(pod v1.Pod, usage map[string]int64) evictionCandidate {
+	candidate := evictionCandidate{name: pod.Namespace + "/" + pod.Name}
+
+	if pod.Spec.Priority != nil {
+		candidate.priority = *pod.Spec.Priority
+	}
+
+	requests := schedulingPodRequests(pod)
+	candidate.request = requests.Memory().Value()
+
+	candidate.used, candidate.hasUsage = usage[candidate.name]
+	return candidate
+}
+
+// evictionPodUsage totals the memory usage of the containers in each pod
+func evictionPodUsage(metrics []v1beta1.PodMetrics) map[string]int64 {
+	usage := make(map[string]int64)
+	for _, pod := range metrics {
+		var total int64
+		for _, container := range pod.Containers {
+			total += container.Usage.Memory().Value()
+		}
+		usage[pod.Namespace+"/"+pod.Name] = total
+	}
+	return usage
+}
+
+// evictionRank orders the candidates the same way the kubelet does under memory pressure and
+// returns the position of each one, starting at 1 for the first pod to be evicted
+func evictionRank(candidates []evictionCandidate) map[string]int64 {
+	ordered := make([]evictionCandidate, len(candidates))
+	copy(ordered, candidates)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return evictionLess(ordered[i], ordered[j])
+	})
+
+	ranks := make(map[string]int64)
+	for i, candidate := range ordered {
+		ranks[candidate.name] = int64(i + 1)
+	}
+	return ranks
+}
+
+// evictionLess returns true when a is evicted before b, pods without usage are evicted first
+// followed by pods using more than their request, then the lowest priority and finally the pod
+// using the most memory above its request
+func evictionLess(a evictionCandidate, b evictionCandidate) bool {
+	if a.hasUsage != b.hasUsage {
+		return !a.hasUsage
+	}
+
+	aExceeds := a.used > a.request
+	bExceeds := b.used > b.request
+	if aExceeds != bExceeds {
+		return aExceeds
+	}
+
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+
+	return a.used-a.request > b.used-b.request
+}
+
+// evictionExceedsColour returns the colour of the EXCEEDS-REQUEST column
+func evictionExceedsColour(exceeds bool) [2]int {
+	if exceeds {
+		return colourWarn
+	}
+	return colourOk
+}
@@ -0,0 +1,77 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// *****************
+// evictionRank
+// *****************
+
+func TestEvictionRank(t *testing.T) {
+	candidates := []evictionCandidate{
+		{name: "default/under", priority: 0, request: 100, used: 50, hasUsage: true},
+		{name: "default/over-small", priority: 0, request: 100, used: 120, hasUsage: true},
+		{name: "default/over-large", priority: 0, request: 100, used: 300, hasUsage: true},
+		{name: "default/over-important", priority: 1000, request: 10, used: 900, hasUsage: true},
+		{name: "default/no-metrics", priority: 2000, request: 100},
+		{name: "default/under-low", priority: -10, request: 100, used: 90, hasUsage: true},
+	}
+
+	expected := []string{
+		"default/no-metrics",
+		"default/over-large",
+		"default/over-small",
+		"default/over-important",
+		"default/under-low",
+		"default/under",
+	}
+
+	ranks := evictionRank(candidates)
+	for i, name := range expected {
+		if ranks[name] != int64(i+1) {
+			t.Errorf("Output %s rank %d not equal to expected %d", name, ranks[name], i+1)
+		}
+	}
+}
+
+// *****************
+// evictionNewCandidate
+// *****************
+
+func TestEvictionNewCandidate(t *testing.T) {
+	priority := int32(100)
+	pod := schedulingTestPod("web", "100m", "64Mi")
+	pod.Namespace = "default"
+	pod.Spec.Priority = &priority
+	pod.Spec.Overhead = v1.ResourceList{v1.ResourceMemory: apires.MustParse("16Mi")}
+
+	metrics := []v1beta1.PodMetrics{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Containers: []v1beta1.ContainerMetrics{
+			{Name: "app", Usage: v1.ResourceList{v1.ResourceMemory: apires.MustParse("60Mi")}},
+			{Name: "log", Usage: v1.ResourceList{v1.ResourceMemory: apires.MustParse("30Mi")}},
+		},
+	}}
+
+	candidate := evictionNewCandidate(pod, evictionPodUsage(metrics))
+	if candidate.priority != 100 {
+		t.Errorf("Output priority %d not equal to expected 100", candidate.priority)
+	}
+	if candidate.request != 80*1024*1024 {
+		t.Errorf("Output request %d not equal to expected %d", candidate.request, 80*1024*1024)
+	}
+	if !candidate.hasUsage || candidate.used != 90*1024*1024 {
+		t.Errorf("Output used %d not equal to expected %d", candidate.used, 90*1024*1024)
+	}
+
+	candidate = evictionNewCandidate(pod, map[string]int64{})
+	if candidate.hasUsage {
+		t.Errorf("Output hasUsage %t not equal to expected false", candidate.hasUsage)
+	}
+}
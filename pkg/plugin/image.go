@@ -1,7 +1,13 @@
 package plugin
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
@@ -14,7 +20,32 @@ var imageDescription = ` Print the the image used for running containers in a po
 and containers can be selected by name. If no name is specified the image details of all pods in
 the current namespace are shown.
 
-The T column in the table output denotes S for Standard and I for init containers`
+The image reference is split into its registry, repository, tag and digest parts, images without a
+registry are expanded to docker.io and single name images are placed under library/. The MISMATCH
+column is set when an image is pinned by digest but the running imageID reports a different digest.
+
+Use --unique to list each image only once along with the number of containers and pods using it.
+
+Use --policy to check each image against a yaml policy file, broken rules are listed in the
+VIOLATION column and the command exits with a non-zero code if any container fails. The policy
+file supports the following optional rules:
+
+  allowedRegistries:          # registry names, wildcards are allowed eg: *.gcr.io
+    - docker.io
+  forbiddenTags:              # an empty string matches images without a tag
+    - latest
+    - ""
+  requireDigest: true         # images must be pinned by digest
+  environments:               # required imagePullPolicy, the first matching namespace pattern wins
+    - name: production
+      namespaces: ["prod-*"]
+      pullPolicy: Always
+  maxAgeDays: 90              # maximum age of images with a date in their tag
+  tagDates:                   # first capture group is parsed using the go time layout
+    - pattern: '(\d{8})'
+      layout: "20060102"
+
+The T column in the table output denotes S for Standard, I for init and K for sidecar containers`
 
 var imageExample = `  # List containers image info from pods
   %[1]s image
@@ -41,7 +72,13 @@ var imageExample = `  # List containers image info from pods
   %[1]s image -l app=web
 
   # List container image info from all pods where the pod label app is either web or mail
-  %[1]s image -l "app in (web,mail)"`
+  %[1]s image -l "app in (web,mail)"
+
+  # List each unique image used by pods in all namespaces along with pod counts
+  %[1]s image -A --unique
+
+  # Check all images in the current namespace against a policy file
+  %[1]s image --policy policy.yaml`
 
 func Image(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
 	log := logger{location: "Image"}
@@ -70,6 +107,20 @@ func Image(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []
 		loopinfo.ShowID = true
 	}
 
+	if cmd.Flag("unique").Value.String() == "true" {
+		log.Debug("loopinfo.ShowUnique = true")
+		loopinfo.ShowUnique = true
+	}
+
+	if policyFile := cmd.Flag("policy").Value.String(); len(policyFile) > 0 {
+		log.Debug("loopinfo.Policy =", policyFile)
+		policy, err := loadImagePolicy(policyFile)
+		if err != nil {
+			return err
+		}
+		loopinfo.Policy = &policy
+	}
+
 	table := Table{}
 	table.ColourOutput = commonFlagList.outputAsColour
 	table.CustomColours = commonFlagList.useTheseColours
@@ -82,22 +133,45 @@ func Image(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []
 		return err
 	}
 
+	if loopinfo.ShowUnique {
+		// swap the container table for the unique image list, any filters have already been applied
+		table = Table{}
+		table.ColourOutput = commonFlagList.outputAsColour
+		table.CustomColours = commonFlagList.useTheseColours
+		loopinfo.inventoryTable(&table)
+	}
+
 	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
 		return err
 	}
 
 	outputTableAs(table, commonFlagList.outputAs)
+
+	if loopinfo.Violations > 0 {
+		return fmt.Errorf("image policy failed for %d containers", loopinfo.Violations)
+	}
+
 	return nil
 
 }
 
 type image struct {
-	ShowID bool
+	ShowID     bool
+	ShowUnique bool
+	Policy     *imagePolicy
+	Violations int
+	inventory  map[string]*imageInventory
+}
+
+type imageInventory struct {
+	ref        imageReference
+	containers int
+	pods       map[string]bool
 }
 
 func (s *image) Headers() []string {
 	return []string{
-		"PULL", "IMAGEID", "CONTAINERID", "IMAGE", "TAG",
+		"PULL", "IMAGEID", "CONTAINERID", "IMAGE", "TAG", "REGISTRY", "REPOSITORY", "DIGEST", "MISMATCH", "VIOLATION",
 	}
 }
 
@@ -116,6 +190,10 @@ func (s *image) HideColumns(info BuilderInformation) []int {
 		hideColumns = append(hideColumns, 1, 2)
 	}
 
+	if s.Policy == nil {
+		hideColumns = append(hideColumns, 9)
+	}
+
 	return hideColumns
 }
 
@@ -140,68 +218,201 @@ func (s *image) BuildEphemeralContainerSpec(container v1.EphemeralContainer, inf
 func (s *image) imageBuildRow(info BuilderInformation, imageName string, pullPolicy string) []Cell {
 	var imageID string
 	var containerID string
+	var runningDigest string
 	var cellList []Cell
 
-	name := imageName
-	tag := ""
-
-	if strings.Contains(imageName, "/") {
-		arrPath := strings.Split(imageName, "/")
-		if c := len(arrPath); c > 0 {
-			tmp := strings.Split(arrPath[c-1], ":")
-			if len(tmp) > 0 {
-				tag = strings.Join(tmp[1:], ":")
-				//calculate the uri length
-				namelen := len(imageName) - len(tag)
-				if len(tag) > 0 {
-					// check a tag was supplied so we dont cut off the last char of the image name
-					namelen--
-				}
-				name = imageName[0:namelen]
-			}
-		}
-	} else {
-		arrImage := strings.Split(imageName, ":")
-		if c := len(arrImage); c > 0 {
-			tag = arrImage[c-1]
-			name = strings.Join(arrImage[:c-1], ":")
-		}
+	log := logger{location: "image:imageBuildRow"}
+	log.Debug("Start")
+
+	ref, err := parseImageReference(imageName)
+	if err != nil {
+		// we still want to show the image even if we cant make sense of it
+		log.Debug("unable to parse image", imageName, err)
+		ref = imageReference{name: imageName, repository: imageName}
 	}
 
-	for _, status := range info.Data.pod.Status.InitContainerStatuses {
-		if status.Image == imageName {
-			imageID = status.ImageID
-			containerID = status.ContainerID
-		}
+	if status := containerStatus(info); status != nil {
+		imageID = status.ImageID
+		containerID = status.ContainerID
 	}
-	for _, status := range info.Data.pod.Status.ContainerStatuses {
-		if status.Image == imageName {
-			imageID = status.ImageID
-			containerID = status.ContainerID
-		}
+
+	if val := strings.Split(imageID, "@"); len(val) == 2 {
+		imageID = val[1]
+		runningDigest = val[1]
 	}
-	for _, status := range info.Data.pod.Status.EphemeralContainerStatuses {
-		if status.Image == imageName {
-			imageID = status.ImageID
-			containerID = status.ContainerID
+
+	// only pinned images can drift from the digest that is actually running
+	mismatch := NewCellText("")
+	if len(ref.digest) > 0 && len(runningDigest) > 0 {
+		differs := ref.digest != runningDigest
+		mismatch = NewCellColourText(setColourBoolean(!differs), strconv.FormatBool(differs))
+	}
+
+	violation := NewCellText("")
+	if s.Policy != nil {
+		if violations := s.Policy.check(ref, pullPolicy, info.Namespace, time.Now()); len(violations) > 0 {
+			s.Violations++
+			violation = NewCellColourText(colourBad, strings.Join(violations, ","))
 		}
 	}
 
-	if val := strings.Split(imageID, "@"); len(val) == 2 {
-		imageID = val[1]
+	if s.ShowUnique {
+		s.addToInventory(info, ref)
 	}
 
 	cellList = append(cellList,
 		NewCellText(pullPolicy),
 		NewCellText(imageID),
 		NewCellText(containerID),
-		NewCellText(name),
-		NewCellText(tag),
+		NewCellText(ref.name),
+		NewCellText(ref.tag),
+		NewCellText(ref.registry),
+		NewCellText(ref.repository),
+		NewCellText(ref.digest),
+		mismatch,
+		violation,
 	)
 
 	return cellList
 }
 
+// addToInventory records the image against the pod so we can output a unique list of images
+func (s *image) addToInventory(info BuilderInformation, ref imageReference) {
+	if s.inventory == nil {
+		s.inventory = make(map[string]*imageInventory)
+	}
+
+	key := ref.String()
+	item, ok := s.inventory[key]
+	if !ok {
+		item = &imageInventory{ref: ref, pods: make(map[string]bool)}
+		s.inventory[key] = item
+	}
+
+	item.containers++
+	item.pods[info.Namespace+"/"+info.PodName] = true
+}
+
+// inventoryTable builds a table containing a single row for each unique image found
+func (s *image) inventoryTable(table *Table) {
+	var keyList []string
+
+	for key := range s.inventory {
+		keyList = append(keyList, key)
+	}
+	sort.Strings(keyList)
+
+	table.SetHeader("REGISTRY", "REPOSITORY", "TAG", "DIGEST", "CONTAINERS", "PODS")
+	for _, key := range keyList {
+		item := s.inventory[key]
+		table.AddRow(
+			NewCellText(item.ref.registry),
+			NewCellText(item.ref.repository),
+			NewCellText(item.ref.tag),
+			NewCellText(item.ref.digest),
+			NewCellInt(strconv.Itoa(item.containers), int64(item.containers)),
+			NewCellInt(strconv.Itoa(len(item.pods)), int64(len(item.pods))),
+		)
+	}
+}
+
 func (s *image) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
 	return [][]Cell{}, nil
 }
+
+// the following expressions follow the distribution reference grammar
+var (
+	imageDomainRegexp    = regexp.MustCompile(`^(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*|\[[a-fA-F0-9:]+\])(?::[0-9]+)?$`)
+	imageComponentRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|[-]+)[a-z0-9]+)*$`)
+	imageTagRegexp       = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	imageDigestRegexp    = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-zA-Z0-9=_-]{32,}$`)
+)
+
+const (
+	imageDefaultRegistry = "docker.io"
+	imageLegacyRegistry  = "index.docker.io"
+	imageOfficialPrefix  = "library/"
+)
+
+type imageReference struct {
+	name       string // image name as written in the spec minus the tag and digest
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+// String returns the fully expanded image reference
+func (r imageReference) String() string {
+	ref := r.repository
+	if len(r.registry) > 0 {
+		ref = r.registry + "/" + ref
+	}
+	if len(r.tag) > 0 {
+		ref += ":" + r.tag
+	}
+	if len(r.digest) > 0 {
+		ref += "@" + r.digest
+	}
+
+	return ref
+}
+
+// parseImageReference splits an image into its registry, repository, tag and digest, images
+// without a registry are expanded to docker.io and single names are placed under library/
+func parseImageReference(image string) (imageReference, error) {
+	ref := imageReference{}
+
+	if len(image) == 0 {
+		return ref, errors.New("image reference is empty")
+	}
+
+	remainder := image
+	if i := strings.Index(remainder, "@"); i >= 0 {
+		ref.digest = remainder[i+1:]
+		remainder = remainder[:i]
+		if !imageDigestRegexp.MatchString(ref.digest) {
+			return ref, errors.New("invalid digest format: " + ref.digest)
+		}
+	}
+
+	// a colon after the last slash can only be a tag, anything before is a registry port
+	if i := strings.LastIndex(remainder, ":"); i > strings.LastIndex(remainder, "/") {
+		ref.tag = remainder[i+1:]
+		remainder = remainder[:i]
+		if !imageTagRegexp.MatchString(ref.tag) {
+			return ref, errors.New("invalid tag format: " + ref.tag)
+		}
+	}
+
+	ref.name = remainder
+	ref.registry = imageDefaultRegistry
+	ref.repository = remainder
+
+	// the first component is only a registry if it looks like a hostname
+	if i := strings.Index(remainder, "/"); i >= 0 {
+		domain := remainder[:i]
+		if strings.ContainsAny(domain, ".:") || domain == "localhost" || strings.ToLower(domain) != domain {
+			if !imageDomainRegexp.MatchString(domain) {
+				return ref, errors.New("invalid registry name: " + domain)
+			}
+			ref.registry = domain
+			ref.repository = remainder[i+1:]
+		}
+	}
+
+	if ref.registry == imageLegacyRegistry {
+		ref.registry = imageDefaultRegistry
+	}
+	if ref.registry == imageDefaultRegistry && !strings.Contains(ref.repository, "/") {
+		ref.repository = imageOfficialPrefix + ref.repository
+	}
+
+	for _, component := range strings.Split(ref.repository, "/") {
+		if !imageComponentRegexp.MatchString(component) {
+			return ref, errors.New("invalid repository name: " + ref.repository)
+		}
+	}
+
+	return ref, nil
+}
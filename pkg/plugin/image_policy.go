@@ -0,0 +1,168 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// imagePolicy is loaded from the file passed to image --policy, all rules are optional and
+// only the rules that are set are checked
+type imagePolicy struct {
+	AllowedRegistries []string                 `json:"allowedRegistries"` // wildcards are allowed, eg: *.gcr.io
+	ForbiddenTags     []string                 `json:"forbiddenTags"`     // an empty string matches images without a tag
+	RequireDigest     bool                     `json:"requireDigest"`
+	Environments      []imagePolicyEnvironment `json:"environments"`
+	MaxAgeDays        int                      `json:"maxAgeDays"`
+	TagDates          []imagePolicyTagDate     `json:"tagDates"`
+}
+
+// imagePolicyEnvironment sets the required pull policy for all namespaces matching the listed patterns
+type imagePolicyEnvironment struct {
+	Name       string   `json:"name"`
+	Namespaces []string `json:"namespaces"`
+	PullPolicy string   `json:"pullPolicy"`
+}
+
+// imagePolicyTagDate extracts a build date from image tags, the first capture group of pattern
+// (or the whole match when there are no groups) is parsed using the go time layout
+type imagePolicyTagDate struct {
+	Pattern string `json:"pattern"`
+	Layout  string `json:"layout"`
+
+	regexp *regexp.Regexp
+}
+
+// loadImagePolicy reads and validates the policy file
+func loadImagePolicy(filename string) (imagePolicy, error) {
+	policy := imagePolicy{}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return policy, err
+	}
+
+	if err := yaml.UnmarshalStrict(content, &policy); err != nil {
+		return policy, fmt.Errorf("unable to read image policy %s: %w", filename, err)
+	}
+
+	for i, tagDate := range policy.TagDates {
+		if len(tagDate.Layout) == 0 {
+			return policy, errors.New("image policy tagDates entry " + tagDate.Pattern + " has no layout")
+		}
+		re, err := regexp.Compile(tagDate.Pattern)
+		if err != nil {
+			return policy, fmt.Errorf("image policy tagDates pattern %s is invalid: %w", tagDate.Pattern, err)
+		}
+		policy.TagDates[i].regexp = re
+	}
+
+	for _, env := range policy.Environments {
+		switch env.PullPolicy {
+		case "Always", "IfNotPresent", "Never":
+		default:
+			return policy, errors.New("image policy environment " + env.Name + " has invalid pullPolicy " + env.PullPolicy)
+		}
+	}
+
+	return policy, nil
+}
+
+// check returns a list of the rules broken by the image, an empty list means the image passed
+func (p imagePolicy) check(ref imageReference, pullPolicy string, namespace string, now time.Time) []string {
+	var violations []string
+
+	if len(p.AllowedRegistries) > 0 {
+		allowed := false
+		for _, registry := range p.AllowedRegistries {
+			if strMatch(ref.registry, registry) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			violations = append(violations, "registry:"+ref.registry)
+		}
+	}
+
+	for _, tag := range p.ForbiddenTags {
+		// a missing tag doesnt matter when the image is pinned by digest
+		if tag == ref.tag && !(len(tag) == 0 && len(ref.digest) > 0) {
+			if len(tag) == 0 {
+				violations = append(violations, "tag:none")
+			} else {
+				violations = append(violations, "tag:"+tag)
+			}
+			break
+		}
+	}
+
+	if p.RequireDigest && len(ref.digest) == 0 {
+		violations = append(violations, "digest")
+	}
+
+	if len(pullPolicy) == 0 || pullPolicy == "-" {
+		pullPolicy = defaultPullPolicy(ref)
+	}
+	for _, env := range p.Environments {
+		matched := false
+		for _, pattern := range env.Namespaces {
+			if strMatch(namespace, pattern) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			if pullPolicy != env.PullPolicy {
+				violations = append(violations, "pull:"+pullPolicy+"!="+env.PullPolicy)
+			}
+			// first matching environment wins
+			break
+		}
+	}
+
+	if p.MaxAgeDays > 0 {
+		if built, ok := p.tagDate(ref.tag); ok {
+			age := int(now.Sub(built).Hours() / 24)
+			if age > p.MaxAgeDays {
+				violations = append(violations, fmt.Sprintf("age:%dd", age))
+			}
+		}
+	}
+
+	return violations
+}
+
+// tagDate returns the date found in the tag using the first matching pattern
+func (p imagePolicy) tagDate(tag string) (time.Time, bool) {
+	for _, tagDate := range p.TagDates {
+		if tagDate.regexp == nil {
+			continue
+		}
+		match := tagDate.regexp.FindStringSubmatch(tag)
+		if match == nil {
+			continue
+		}
+		value := match[0]
+		if len(match) > 1 {
+			value = match[1]
+		}
+		if built, err := time.Parse(tagDate.Layout, value); err == nil {
+			return built, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// defaultPullPolicy returns the pull policy the api server would apply when none is set
+func defaultPullPolicy(ref imageReference) string {
+	if ref.tag == "latest" || (len(ref.tag) == 0 && len(ref.digest) == 0) {
+		return "Always"
+	}
+	return "IfNotPresent"
+}
@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// *****************
+// parseImageReference
+// *****************
+
+type parseImageReferenceTest struct {
+	arg1               string
+	expectedName       string
+	expectedRegistry   string
+	expectedRepository string
+	expectedTag        string
+	expectedDigest     string
+	expectedErr        bool
+}
+
+var testDigest = "sha256:d8e5a4ad07c08d05d9bd8aba2ba5ab1d62e41a90e2a18d6cc0d0b6a89e6a3a1b"
+
+var parseImageReferenceTests = []parseImageReferenceTest{
+	{"nginx", "nginx", "docker.io", "library/nginx", "", "", false},
+	{"nginx:1.25", "nginx", "docker.io", "library/nginx", "1.25", "", false},
+	{"bitnami/redis:7.0", "bitnami/redis", "docker.io", "bitnami/redis", "7.0", "", false},
+	{"index.docker.io/nginx", "index.docker.io/nginx", "docker.io", "library/nginx", "", "", false},
+	{"registry:5000/repo", "registry:5000/repo", "registry:5000", "repo", "", "", false},
+	{"registry:5000/team/repo:v2", "registry:5000/team/repo", "registry:5000", "team/repo", "v2", "", false},
+	{"localhost/app", "localhost/app", "localhost", "app", "", "", false},
+	{"quay.io/prometheus/node-exporter:v1.6.1", "quay.io/prometheus/node-exporter", "quay.io", "prometheus/node-exporter", "v1.6.1", "", false},
+	{"repo@" + testDigest, "repo", "docker.io", "library/repo", "", testDigest, false},
+	{"gcr.io/distroless/static:nonroot@" + testDigest, "gcr.io/distroless/static", "gcr.io", "distroless/static", "nonroot", testDigest, false},
+	{"[::1]:5000/repo:tag", "[::1]:5000/repo", "[::1]:5000", "repo", "tag", "", false},
+	{"", "", "", "", "", "", true},
+	{"repo@sha256:short", "", "", "", "", "", true},
+	{"Uppercase/Repo", "", "", "", "", "", true},
+	{"repo:-badtag", "", "", "", "", "", true},
+}
+
+func TestParseImageReference(t *testing.T) {
+
+	for _, test := range parseImageReferenceTests {
+		output, err := parseImageReference(test.arg1)
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("Expected error for image %s", test.arg1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error %s for image %s", err, test.arg1)
+			continue
+		}
+
+		if output.name != test.expectedName {
+			t.Errorf("Output name %s not equal to expected %s", output.name, test.expectedName)
+		}
+		if output.registry != test.expectedRegistry {
+			t.Errorf("Output registry %s not equal to expected %s", output.registry, test.expectedRegistry)
+		}
+		if output.repository != test.expectedRepository {
+			t.Errorf("Output repository %s not equal to expected %s", output.repository, test.expectedRepository)
+		}
+		if output.tag != test.expectedTag {
+			t.Errorf("Output tag %s not equal to expected %s", output.tag, test.expectedTag)
+		}
+		if output.digest != test.expectedDigest {
+			t.Errorf("Output digest %s not equal to expected %s", output.digest, test.expectedDigest)
+		}
+	}
+}
+
+// *****************
+// imagePolicy.check
+// *****************
+
+type imagePolicyCheckTest struct {
+	arg1     string
+	arg2     string
+	arg3     string
+	expected string
+}
+
+var testImagePolicy = imagePolicy{
+	AllowedRegistries: []string{"docker.io", "*.gcr.io"},
+	ForbiddenTags:     []string{"latest", ""},
+	Environments: []imagePolicyEnvironment{
+		{Name: "production", Namespaces: []string{"prod-*"}, PullPolicy: "Always"},
+	},
+	MaxAgeDays: 30,
+	TagDates: []imagePolicyTagDate{
+		{Pattern: `(\d{8})`, Layout: "20060102", regexp: regexp.MustCompile(`(\d{8})`)},
+	},
+}
+
+var imagePolicyCheckTests = []imagePolicyCheckTest{
+	{"nginx:1.25", "IfNotPresent", "default", ""},
+	{"nginx", "", "default", "tag:none"},
+	{"nginx:latest", "Always", "default", "tag:latest"},
+	{"nginx@" + testDigest, "IfNotPresent", "default", ""},
+	{"quay.io/app/web:v1", "IfNotPresent", "default", "registry:quay.io"},
+	{"eu.gcr.io/app/web:v1", "IfNotPresent", "prod-eu", "pull:IfNotPresent!=Always"},
+	{"eu.gcr.io/app/web:v1", "Always", "prod-eu", ""},
+	{"app/web:build-20230101", "IfNotPresent", "default", "age:45d"},
+	{"app/web:build-20230201", "IfNotPresent", "default", ""},
+}
+
+func TestImagePolicyCheck(t *testing.T) {
+	now := time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC)
+
+	for _, test := range imagePolicyCheckTests {
+		ref, err := parseImageReference(test.arg1)
+		if err != nil {
+			t.Errorf("Unexpected error %s for image %s", err, test.arg1)
+			continue
+		}
+
+		output := strings.Join(testImagePolicy.check(ref, test.arg2, test.arg3, now), ",")
+		if output != test.expected {
+			t.Errorf("Output %s not equal to expected %s, for image %s", output, test.expected, test.arg1)
+		}
+	}
+}
@@ -10,9 +10,12 @@ import (
 	a1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	v1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
@@ -22,6 +25,8 @@ const TypeIDContainer string = "C"
 const TypeNameContainer string = "Container"
 const TypeIDInitContainer string = "I"
 const TypeNameInitContainer string = "InitContainer"
+const TypeIDSidecarContainer string = "K"
+const TypeNameSidecarContainer string = "SidecarContainer"
 const TypeIDEphemeralContainer string = "E"
 const TypeNameEphemeralContainer string = "EphemeralContainer"
 const TypeIDPod string = "P"
@@ -41,24 +46,42 @@ const TypeNameJob string = "Job"
 const TypeIDCronJob string = "O"
 const TypeNameCronJob string = "CronJob"
 
+// node name used as the tree root for pods that have not been scheduled to a node
+const unscheduledNodeName string = "<unscheduled>"
+
 // const TypeID string= ""
 // const TypeName string = ""
 
 type Connector struct {
 	clientSet      kubernetes.Clientset
+	restConfig     *rest.Config
 	metricSet      metricsclientset.Clientset
 	Flags          commonFlags
 	configFlags    *genericclioptions.ConfigFlags
 	metricFlags    *genericclioptions.ConfigFlags
 	configMapArray map[string]map[string]string
 	setNameSpace   string
-	podList        []v1.Pod                     // List of Pods
-	replicaList    map[string][]a1.ReplicaSet   // list of ReplicaSets
-	daemonList     map[string][]a1.DaemonSet    // list of DaemonSets
-	statefulList   map[string][]a1.StatefulSet  // list of StatefulSet
-	deploymentList map[string][]a1.Deployment   // list of Deployments
-	jobList        map[string][]batchv1.Job     // list of k8s Jobs
-	cronJobList    map[string][]batchv1.CronJob // list of k8s CronJobs
+	podList        []v1.Pod                              // List of Pods
+	replicaList    map[string][]a1.ReplicaSet            // list of ReplicaSets
+	daemonList     map[string][]a1.DaemonSet             // list of DaemonSets
+	statefulList   map[string][]a1.StatefulSet           // list of StatefulSet
+	deploymentList map[string][]a1.Deployment            // list of Deployments
+	jobList        map[string][]batchv1.Job              // list of k8s Jobs
+	cronJobList    map[string][]batchv1.CronJob          // list of k8s CronJobs
+	claimList      map[string][]v1.PersistentVolumeClaim // list of PersistentVolumeClaims
+	volumeList     []v1.PersistentVolume                 // list of PersistentVolumes
+	storageList    []storagev1.StorageClass              // list of StorageClasses
+	objectKeys     map[string]objectKeyList              // key names found in each ConfigMap and Secret
+	eventList      map[string][]v1.Event                 // list of pod Events
+	nodeList       []v1.Node                             // list of all Nodes in the cluster
+	nodePodList    map[string][]v1.Pod                   // list of running Pods on each Node
+	allMetricList  []v1beta1.PodMetrics                  // metrics of Pods from every namespace
+}
+
+type objectKeyList struct {
+	keys  []string
+	found bool
+	err   error // set when the object could not be read for any reason other than not existing
 }
 
 type ParentData struct {
@@ -121,6 +144,7 @@ func (c *Connector) LoadConfig(configFlags *genericclioptions.ConfigFlags) error
 		return fmt.Errorf("failed to create clientset: %w", err)
 	}
 	c.clientSet = *clientset
+	c.restConfig = config
 	return nil
 }
 
@@ -353,47 +377,65 @@ func (c *Connector) GetMetricPods(podNameList []string) ([]v1beta1.PodMetrics, e
 	}
 }
 
-func (c *Connector) GetConfigMaps(configMapName string) (v1.ConfigMap, error) {
-
-	namespace := c.GetNamespace(c.Flags.allNamespaces)
+func (c *Connector) GetConfigMaps(configMapName string, namespace string) (v1.ConfigMap, error) {
 
 	if len(configMapName) == 0 {
 		return v1.ConfigMap{}, nil
 	}
 
 	cm, err := c.clientSet.CoreV1().ConfigMaps(namespace).Get(context.TODO(), configMapName, metav1.GetOptions{})
-	if err == nil {
-		return *cm, nil
+	if err != nil {
+		return v1.ConfigMap{}, err
 	}
 
-	return v1.ConfigMap{}, nil
+	return *cm, nil
 }
 
 func (c *Connector) GetConfigMapValue(configMap string, key string) string {
-	var val map[string]map[string]string
-
 	if len(configMap) <= 0 {
 		return ""
 	}
 
-	if _, ok := c.configMapArray[configMap]; !ok {
-		cm, err := c.GetConfigMaps(configMap)
-		if err != nil {
-			c.configMapArray[configMap] = make(map[string]string)
-			return ""
-		}
+	data, _, err := c.GetConfigMapData(configMap, c.GetNamespace(c.Flags.allNamespaces))
+	if err != nil {
+		return ""
+	}
+	return data[key]
+}
 
-		if len(c.configMapArray) > 0 {
-			val = c.configMapArray
-		} else {
-			val = make(map[string]map[string]string)
-		}
-		val[configMap] = cm.Data
-		c.configMapArray = val
+// GetConfigMapData returns all the key value pairs stored in the named configmap, false is returned
+// if the configmap does not exist. Each configmap is only requested once and the result is cached
+// for later calls
+func (c *Connector) GetConfigMapData(configMap string, namespace string) (map[string]string, bool, error) {
+	if len(configMap) <= 0 {
+		return map[string]string{}, false, nil
+	}
+
+	if c.configMapArray == nil {
+		c.configMapArray = make(map[string]map[string]string)
+	}
+
+	id := namespace + "/" + configMap
+	if data, ok := c.configMapArray[id]; ok {
+		// missing configmaps are cached as nil
+		return data, data != nil, nil
+	}
 
+	cm, err := c.GetConfigMaps(configMap, namespace)
+	if apierrors.IsNotFound(err) {
+		c.configMapArray[id] = nil
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to retrieve configmap %s: %w", id, err)
+	}
+
+	c.configMapArray[id] = cm.Data
+	if cm.Data == nil {
+		c.configMapArray[id] = map[string]string{}
 	}
 
-	return c.configMapArray[configMap][key]
+	return c.configMapArray[id], true, nil
 }
 
 // GetNamespace retrieves the namespace that is currently set as default
@@ -947,6 +989,10 @@ func (c *Connector) BuildOwnersList() []*LeafNode {
 
 	for _, pod := range c.podList {
 		nodename := pod.Spec.NodeName
+		if len(nodename) == 0 {
+			// pods that are waiting to be scheduled are grouped together under a single root
+			nodename = unscheduledNodeName
+		}
 		// first create a list with the pod as the first entry
 		parentList := []ParentData{{
 			name:          pod.Name,
@@ -1089,3 +1135,294 @@ func (c *Connector) appendParents(current []ParentData, oref []metav1.OwnerRefer
 
 	return current
 }
+
+// GetPersistentVolumeClaim returns the named PersistentVolumeClaim from the namespace or nil if it cant be found
+func (c *Connector) GetPersistentVolumeClaim(claimName string, namespace string) *v1.PersistentVolumeClaim {
+	if _, ok := c.claimList[namespace]; !ok {
+		c.LoadPersistentVolumeClaim(namespace)
+	}
+
+	for _, p := range c.claimList[namespace] {
+		if p.Name == claimName {
+			return &p
+		}
+	}
+	return nil
+}
+
+// LoadPersistentVolumeClaim retrieves all claims in the namespace, the label selector is not used as
+// it applies to the pods and not the claims they use
+func (c *Connector) LoadPersistentVolumeClaim(namespace string) error {
+	log := logger{location: "k8sconnector:LoadPersistentVolumeClaim"}
+	log.Debug("Start")
+
+	if c.claimList == nil {
+		c.claimList = make(map[string][]v1.PersistentVolumeClaim)
+	}
+
+	pvc, err := c.clientSet.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		// save an empty list so we dont keep asking the server
+		c.claimList[namespace] = []v1.PersistentVolumeClaim{}
+		return fmt.Errorf("failed to retrieve PersistentVolumeClaim list from server: %w", err)
+	}
+
+	c.claimList[namespace] = pvc.Items
+	return nil
+}
+
+// GetPersistentVolume returns the named PersistentVolume or nil if it cant be found
+func (c *Connector) GetPersistentVolume(volumeName string) *v1.PersistentVolume {
+	if c.volumeList == nil {
+		c.LoadPersistentVolume()
+	}
+
+	for _, p := range c.volumeList {
+		if p.Name == volumeName {
+			return &p
+		}
+	}
+	return nil
+}
+
+// LoadPersistentVolume retrieves all PersistentVolumes, volumes are cluster wide so no namespace is needed
+func (c *Connector) LoadPersistentVolume() error {
+	log := logger{location: "k8sconnector:LoadPersistentVolume"}
+	log.Debug("Start")
+
+	pv, err := c.clientSet.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		c.volumeList = []v1.PersistentVolume{}
+		return fmt.Errorf("failed to retrieve PersistentVolume list from server: %w", err)
+	}
+
+	c.volumeList = pv.Items
+	return nil
+}
+
+// GetStorageClass returns the named StorageClass or nil if it cant be found
+func (c *Connector) GetStorageClass(className string) *storagev1.StorageClass {
+	if c.storageList == nil {
+		c.LoadStorageClass()
+	}
+
+	for _, s := range c.storageList {
+		if s.Name == className {
+			return &s
+		}
+	}
+	return nil
+}
+
+// LoadStorageClass retrieves all StorageClasses, classes are cluster wide so no namespace is needed
+func (c *Connector) LoadStorageClass() error {
+	log := logger{location: "k8sconnector:LoadStorageClass"}
+	log.Debug("Start")
+
+	sc, err := c.clientSet.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		c.storageList = []storagev1.StorageClass{}
+		return fmt.Errorf("failed to retrieve StorageClass list from server: %w", err)
+	}
+
+	c.storageList = sc.Items
+	return nil
+}
+
+// GetConfigMapKeys returns the list of keys stored in the named ConfigMap, false is returned if
+// the ConfigMap does not exist and an error if it could not be read
+func (c *Connector) GetConfigMapKeys(configMapName string, namespace string) ([]string, bool, error) {
+	return c.getObjectKeys("ConfigMap", configMapName, namespace)
+}
+
+// GetSecretKeys returns the list of keys stored in the named Secret, false is returned if the
+// Secret does not exist and an error if it could not be read, only the key names are kept the
+// values are never stored
+func (c *Connector) GetSecretKeys(secretName string, namespace string) ([]string, bool, error) {
+	return c.getObjectKeys("Secret", secretName, namespace)
+}
+
+// getObjectKeys retrieves and caches the key names of a ConfigMap or Secret
+func (c *Connector) getObjectKeys(kind string, name string, namespace string) ([]string, bool, error) {
+	log := logger{location: "k8sconnector:getObjectKeys"}
+	log.Debug("Start")
+
+	if c.objectKeys == nil {
+		c.objectKeys = make(map[string]objectKeyList)
+	}
+
+	id := kind + "/" + namespace + "/" + name
+	if list, ok := c.objectKeys[id]; ok {
+		return list.keys, list.found, list.err
+	}
+
+	keyList := []string{}
+	var err error
+
+	switch kind {
+	case "ConfigMap":
+		var cm *v1.ConfigMap
+		cm, err = c.clientSet.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err == nil {
+			for k := range cm.Data {
+				keyList = append(keyList, k)
+			}
+			for k := range cm.BinaryData {
+				keyList = append(keyList, k)
+			}
+		}
+
+	case "Secret":
+		var secret *v1.Secret
+		secret, err = c.clientSet.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err == nil {
+			for k := range secret.Data {
+				keyList = append(keyList, k)
+			}
+		}
+	}
+
+	if err != nil {
+		log.Debug("failed to read", id, err)
+	}
+
+	list := newObjectKeyList(id, keyList, err)
+	c.objectKeys[id] = list
+
+	return list.keys, list.found, list.err
+}
+
+// newObjectKeyList creates the cached result of reading an object, a missing object is not an error
+// as the caller reports it as missing, any other error means we dont know if the keys exist
+func newObjectKeyList(id string, keyList []string, err error) objectKeyList {
+	if apierrors.IsNotFound(err) {
+		return objectKeyList{keys: []string{}, found: false}
+	}
+	if err != nil {
+		return objectKeyList{keys: []string{}, found: false, err: fmt.Errorf("failed to retrieve %s: %w", id, err)}
+	}
+	return objectKeyList{keys: keyList, found: true}
+}
+
+// GetPodEvents returns all events that were recorded against the pod, the uid is checked so events
+// from an earlier pod with the same name (like a StatefulSet pod that was replaced) are skipped
+func (c *Connector) GetPodEvents(pod v1.Pod) []v1.Event {
+	var events []v1.Event
+
+	if _, ok := c.eventList[pod.Namespace]; !ok {
+		c.LoadPodEvents(pod.Namespace)
+	}
+
+	for _, e := range c.eventList[pod.Namespace] {
+		if e.InvolvedObject.Name != pod.Name {
+			continue
+		}
+		if len(e.InvolvedObject.UID) > 0 && len(pod.UID) > 0 && e.InvolvedObject.UID != pod.UID {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// LoadPodEvents retrieves all events in the namespace that were recorded against pods
+func (c *Connector) LoadPodEvents(namespace string) error {
+	log := logger{location: "k8sconnector:LoadPodEvents"}
+	log.Debug("Start")
+
+	if c.eventList == nil {
+		c.eventList = make(map[string][]v1.Event)
+	}
+
+	events, err := c.clientSet.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=Pod",
+	})
+	if err != nil {
+		c.eventList[namespace] = []v1.Event{}
+		return fmt.Errorf("failed to retrieve Event list from server: %w", err)
+	}
+
+	c.eventList[namespace] = events.Items
+	return nil
+}
+
+// GetNodeList returns every node in the cluster, unlike GetNodes the label selector is not applied
+func (c *Connector) GetNodeList() []v1.Node {
+	if c.nodeList == nil {
+		c.LoadNodeList()
+	}
+
+	return c.nodeList
+}
+
+// LoadNodeList retrieves all Nodes, nodes are cluster wide so no namespace is needed
+func (c *Connector) LoadNodeList() error {
+	log := logger{location: "k8sconnector:LoadNodeList"}
+	log.Debug("Start")
+
+	nodes, err := c.clientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		c.nodeList = []v1.Node{}
+		return fmt.Errorf("failed to retrieve Node list from server: %w", err)
+	}
+
+	c.nodeList = nodes.Items
+	return nil
+}
+
+// GetNodePods returns the pods from all namespaces that are scheduled on the named node and have
+// not yet finished
+func (c *Connector) GetNodePods(nodeName string) []v1.Pod {
+	if c.nodePodList == nil {
+		c.LoadNodePods()
+	}
+
+	return c.nodePodList[nodeName]
+}
+
+// LoadNodePods retrieves all scheduled pods that have not finished from every namespace and groups
+// them by node name
+func (c *Connector) LoadNodePods() error {
+	log := logger{location: "k8sconnector:LoadNodePods"}
+	log.Debug("Start")
+
+	c.nodePodList = make(map[string][]v1.Pod)
+
+	pods, err := c.clientSet.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{
+		FieldSelector: "spec.nodeName!=,status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve Pod list from server: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		c.nodePodList[pod.Spec.NodeName] = append(c.nodePodList[pod.Spec.NodeName], pod)
+	}
+	return nil
+}
+
+// GetAllMetricPods returns the metrics of the pods in every namespace, LoadMetricConfig must be
+// called first
+func (c *Connector) GetAllMetricPods() []v1beta1.PodMetrics {
+	if c.allMetricList == nil {
+		c.LoadAllMetricPods()
+	}
+
+	return c.allMetricList
+}
+
+// LoadAllMetricPods retrieves the metrics of the pods in every namespace
+func (c *Connector) LoadAllMetricPods() error {
+	log := logger{location: "k8sconnector:LoadAllMetricPods"}
+	log.Debug("Start")
+
+	c.allMetricList = []v1beta1.PodMetrics{}
+
+	podList, err := c.metricSet.MetricsV1beta1().PodMetricses("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve pod list from metrics: %w", err)
+	}
+
+	c.allMetricList = podList.Items
+	return nil
+}
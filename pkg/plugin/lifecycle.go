@@ -1,6 +1,9 @@
 package plugin
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -13,7 +16,14 @@ var lifecycleShort = "Show lifecycle actions for each container in a named pod"
 var lifecycleDescription = ` Prints lifecycle actions for individual containers. If no name is specified the
 configured actions of all pods in the current namespace are shown.
 
-The T column in the table output denotes S for Standard, I for init and E for Ephemerial containers`
+The GRACE column shows the pods terminationGracePeriodSeconds and STOP-SIGNAL the signal sent to the
+container when its stopped. SHUTDOWN checks the preStop sleep plus the expected time taken to drain
+connections (set using --drain-seconds) completes before the grace period ends, when it doesnt the
+container is killed while connections are still being drained. The preStop sleep is read from the
+Sleep handler or an exec handler that calls sleep. Containers without a lifecycle handler are listed
+with a handler of - so containers missing a preStop hook can be found.
+
+The T column in the table output denotes S for Standard, I for init, K for sidecar and E for Ephemerial containers`
 
 var lifecycleExample = `  # List individual container lifecycle events from pods
   %[1]s lifecycle
@@ -40,13 +50,24 @@ var lifecycleExample = `  # List individual container lifecycle events from pods
   %[1]s lifecycle -l app=web
 
   # List lifecycle events from all containers where the pod label app is either web or mail
-  %[1]s lifecycle -l "app in (web,mail)"`
+  %[1]s lifecycle -l "app in (web,mail)"
+
+  # List lifecycle events and check containers have time to drain connections for 20 seconds
+  # before they are killed
+  %[1]s lifecycle --drain-seconds 20`
 
 type lifecycleAction struct {
 	action     string
 	actionName string
+	sleep      int64 // number of seconds the handler sleeps for
 }
 
+// default value used by the api server when terminationGracePeriodSeconds is not set
+const lifecycleDefaultGrace = 30
+
+// matches the sleep command when its used inside an exec handler
+var lifecycleSleepRegexp = regexp.MustCompile(`(?:^|[\s;&|/])sleep\s+(\d+)`)
+
 func Lifecycle(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
 
 	log := logger{location: "LifeCycle"}
@@ -78,6 +99,12 @@ func Lifecycle(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, arg
 	builder.Table = &table
 	builder.ShowTreeView = commonFlagList.showTreeView
 
+	drainSeconds, err := strconv.ParseInt(cmd.Flag("drain-seconds").Value.String(), 10, 64)
+	if err != nil {
+		return err
+	}
+	loopinfo.DrainSeconds = drainSeconds
+
 	if err := builder.Build(&loopinfo); err != nil {
 		return err
 	}
@@ -92,11 +119,12 @@ func Lifecycle(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, arg
 }
 
 type lifecycle struct {
+	DrainSeconds int64
 }
 
 func (s *lifecycle) Headers() []string {
 	return []string{
-		"LIFECYCLE", "HANDLER", "ACTION",
+		"LIFECYCLE", "HANDLER", "ACTION", "GRACE", "STOP-SIGNAL", "SHUTDOWN",
 	}
 }
 
@@ -113,39 +141,85 @@ func (s *lifecycle) HideColumns(info BuilderInformation) []int {
 }
 
 func (s *lifecycle) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	out := []Cell{
-		NewCellText(""),
-		NewCellText(""),
-		NewCellText(""),
+	out := make([]Cell, len(s.Headers()))
+	for i := range out {
+		out[i] = NewCellText("")
 	}
 	return out, nil
 }
 
 func (s *lifecycle) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
-	out := [][]Cell{}
-	lifecycleList := s.buildLifecycleList(container.Lifecycle)
-	for name, action := range lifecycleList {
-		out = append(out, s.lifecycleBuildRow(info, name, action))
-	}
-	return out, nil
+	return s.lifecycleBuildRows(info, container.Lifecycle), nil
 }
 
 func (s *lifecycle) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return s.lifecycleBuildRows(info, container.Lifecycle), nil
+}
+
+// lifecycleBuildRows returns a row for each configured lifecycle handler, containers without a
+// handler still get a single row so the grace period and shutdown safety are shown
+func (s *lifecycle) lifecycleBuildRows(info BuilderInformation, lifecycle *v1.Lifecycle) [][]Cell {
 	out := [][]Cell{}
-	return out, nil
+	if lifecycle == nil {
+		lifecycle = &v1.Lifecycle{}
+	}
+	lifecycleList := s.buildLifecycleList(lifecycle)
+
+	// always output the handlers in the order they are called
+	for _, name := range []string{"postStart", "preStop"} {
+		if action, ok := lifecycleList[name]; ok {
+			out = append(out, s.lifecycleBuildRow(info, name, action, lifecycle))
+		}
+	}
 
+	if len(out) == 0 {
+		out = append(out, s.lifecycleBuildRow(info, "-", lifecycleAction{}, lifecycle))
+	}
+	return out
 }
 
-func (s *lifecycle) lifecycleBuildRow(info BuilderInformation, handlerName string, lifecycles lifecycleAction) []Cell {
+func (s *lifecycle) lifecycleBuildRow(info BuilderInformation, handlerName string, lifecycles lifecycleAction, lifecycle *v1.Lifecycle) []Cell {
+	grace := int64(lifecycleDefaultGrace)
+	if info.Data.pod.Spec.TerminationGracePeriodSeconds != nil {
+		grace = *info.Data.pod.Spec.TerminationGracePeriodSeconds
+	}
+
+	stopSignal := ""
+	if lifecycle.StopSignal != nil {
+		stopSignal = string(*lifecycle.StopSignal)
+	}
+
+	var preStopSleep int64
+	if lifecycle.PreStop != nil {
+		preStopSleep = s.buildLifecycleAction(lifecycle.PreStop).sleep
+	}
 
 	return []Cell{
 		NewCellText(handlerName),
 		NewCellText(lifecycles.actionName),
 		NewCellText(lifecycles.action),
+		NewCellInt(fmt.Sprintf("%d", grace), grace),
+		NewCellText(stopSignal),
+		shutdownSafetyCell(lifecycle.PreStop != nil, preStopSleep, s.DrainSeconds, grace),
+	}
+}
+
+// shutdownSafetyCell checks the preStop sleep and drain time completes before the grace period ends
+func shutdownSafetyCell(hasPreStop bool, preStopSleep int64, drainSeconds int64, grace int64) Cell {
+	shutdown := preStopSleep + drainSeconds
+	if shutdown > grace {
+		return NewCellColourText(colourBad, fmt.Sprintf("unsafe %ds>%ds", shutdown, grace))
 	}
+
+	if !hasPreStop {
+		// without a preStop hook the container is stopped while its still receiving traffic
+		return NewCellColourText(colourWarn, "no-prestop")
+	}
+
+	return NewCellColourText(colourOk, fmt.Sprintf("safe %ds<=%ds", shutdown, grace))
 }
 
-// check each type of probe and return a list
+// check each type of lifecycle handler and return a list
 func (s *lifecycle) buildLifecycleList(lifecycle *v1.Lifecycle) map[string]lifecycleAction {
 	lifeCycleList := make(map[string]lifecycleAction)
 	if lifecycle == nil {
@@ -153,7 +227,7 @@ func (s *lifecycle) buildLifecycleList(lifecycle *v1.Lifecycle) map[string]lifec
 	}
 
 	if lifecycle.PostStart != nil {
-		lifeCycleList["preStop"] = s.buildLifecycleAction(lifecycle.PostStart)
+		lifeCycleList["postStart"] = s.buildLifecycleAction(lifecycle.PostStart)
 	}
 
 	if lifecycle.PreStop != nil {
@@ -171,6 +245,17 @@ func (s *lifecycle) buildLifecycleAction(lifecycle *v1.LifecycleHandler) lifecyc
 	if lifecycle.Exec != nil {
 		item.actionName = "Exec"
 		item.action = strings.Join(lifecycle.Exec.Command, " ")
+		if match := lifecycleSleepRegexp.FindStringSubmatch(item.action); match != nil {
+			item.sleep, _ = strconv.ParseInt(match[1], 10, 64)
+		}
+		return item
+	}
+
+	// translate Sleep action
+	if lifecycle.Sleep != nil {
+		item.actionName = "Sleep"
+		item.action = fmt.Sprintf("%ds", lifecycle.Sleep.Seconds)
+		item.sleep = lifecycle.Sleep.Seconds
 		return item
 	}
 
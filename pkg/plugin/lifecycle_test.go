@@ -0,0 +1,116 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// *****************
+// shutdownSafetyCell
+// *****************
+
+type shutdownSafetyCellTest struct {
+	hasPreStop     bool
+	preStopSleep   int64
+	drainSeconds   int64
+	grace          int64
+	expectedText   string
+	expectedColour [2]int
+}
+
+var shutdownSafetyCellTests = []shutdownSafetyCellTest{
+	{true, 10, 15, 30, "safe 25s<=30s", colourOk},
+	{true, 20, 15, 30, "unsafe 35s>30s", colourBad},
+	{false, 0, 10, 30, "no-prestop", colourWarn},
+	{false, 0, 40, 30, "unsafe 40s>30s", colourBad},
+	{false, 0, 0, 30, "no-prestop", colourWarn},
+}
+
+func TestShutdownSafetyCell(t *testing.T) {
+	for _, test := range shutdownSafetyCellTests {
+		output := shutdownSafetyCell(test.hasPreStop, test.preStopSleep, test.drainSeconds, test.grace)
+		if output.text != test.expectedText {
+			t.Errorf("Output %s not equal to expected %s", output.text, test.expectedText)
+		}
+		if output.colour != test.expectedColour {
+			t.Errorf("Output colour %v not equal to expected %v, for %s", output.colour, test.expectedColour, test.expectedText)
+		}
+	}
+}
+
+// *****************
+// buildLifecycleAction
+// *****************
+
+type buildLifecycleActionTest struct {
+	arg1           v1.LifecycleHandler
+	expectedName   string
+	expectedAction string
+	expectedSleep  int64
+}
+
+var buildLifecycleActionTests = []buildLifecycleActionTest{
+	{v1.LifecycleHandler{Exec: &v1.ExecAction{Command: []string{"/bin/sh", "-c", "sleep 15"}}}, "Exec", "/bin/sh -c sleep 15", 15},
+	{v1.LifecycleHandler{Exec: &v1.ExecAction{Command: []string{"/bin/sh", "-c", "nginx -s quit; /bin/sleep 5"}}}, "Exec", "/bin/sh -c nginx -s quit; /bin/sleep 5", 5},
+	{v1.LifecycleHandler{Exec: &v1.ExecAction{Command: []string{"/usr/bin/nosleep", "20"}}}, "Exec", "/usr/bin/nosleep 20", 0},
+	{v1.LifecycleHandler{Sleep: &v1.SleepAction{Seconds: 12}}, "Sleep", "12s", 12},
+}
+
+func TestBuildLifecycleAction(t *testing.T) {
+	s := lifecycle{}
+
+	for _, test := range buildLifecycleActionTests {
+		output := s.buildLifecycleAction(&test.arg1)
+		if output.actionName != test.expectedName {
+			t.Errorf("Output name %s not equal to expected %s", output.actionName, test.expectedName)
+		}
+		if output.action != test.expectedAction {
+			t.Errorf("Output action %s not equal to expected %s", output.action, test.expectedAction)
+		}
+		if output.sleep != test.expectedSleep {
+			t.Errorf("Output sleep %d not equal to expected %d, for %s", output.sleep, test.expectedSleep, test.expectedAction)
+		}
+	}
+}
+
+// *****************
+// lifecycleBuildRows
+// *****************
+
+func TestLifecycleBuildRows(t *testing.T) {
+	s := lifecycle{}
+	grace := int64(10)
+	info := BuilderInformation{Data: ParentData{pod: v1.Pod{Spec: v1.PodSpec{TerminationGracePeriodSeconds: &grace}}}}
+
+	tests := []struct {
+		name             string
+		lifecycle        *v1.Lifecycle
+		expectedHandlers []string
+		expectedShutdown string
+	}{
+		{"no hooks", nil, []string{"-"}, "no-prestop"},
+		{"empty lifecycle", &v1.Lifecycle{}, []string{"-"}, "no-prestop"},
+		{"preStop", &v1.Lifecycle{PreStop: &v1.LifecycleHandler{Sleep: &v1.SleepAction{Seconds: 5}}}, []string{"preStop"}, "safe 5s<=10s"},
+		{"both hooks", &v1.Lifecycle{
+			PostStart: &v1.LifecycleHandler{Exec: &v1.ExecAction{Command: []string{"true"}}},
+			PreStop:   &v1.LifecycleHandler{Sleep: &v1.SleepAction{Seconds: 20}},
+		}, []string{"postStart", "preStop"}, "unsafe 20s>10s"},
+	}
+
+	for _, test := range tests {
+		rows := s.lifecycleBuildRows(info, test.lifecycle)
+		if len(rows) != len(test.expectedHandlers) {
+			t.Errorf("%s: output %d rows not equal to expected %d", test.name, len(rows), len(test.expectedHandlers))
+			continue
+		}
+		for i, row := range rows {
+			if row[0].text != test.expectedHandlers[i] {
+				t.Errorf("%s: output handler %s not equal to expected %s", test.name, row[0].text, test.expectedHandlers[i])
+			}
+			if row[3].text != "10" || row[5].text != test.expectedShutdown {
+				t.Errorf("%s: output grace %s shutdown %s not equal to expected 10 %s", test.name, row[3].text, row[5].text, test.expectedShutdown)
+			}
+		}
+	}
+}
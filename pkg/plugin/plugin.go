@@ -47,9 +47,16 @@ const (
 )
 
 func InitSubCommands(rootCmd *cobra.Command) {
-	var includeInitShort string = "include init container(s) in the output, by default init containers are hidden"
+	var includeInitShort string = "include init container(s) in the output, by default init containers are hidden, sidecar containers are always shown"
 	var odditiesShort string = "show only the outlier rows that dont fall within the computed range"
 	var sizeShort string = "allows conversion to the selected size rather then the default megabyte output"
+	var recommendShort string = "show recommended requests and limits calculated from the current usage"
+	var requestHeadroomShort string = "percentage added to the average usage to give the recommended request"
+	var limitHeadroomShort string = "percentage added to the peak usage to give the recommended limit"
+	var samplesShort string = "number of times usage is read from the metrics server when using --recommend"
+	var intervalShort string = "time to wait between each sample, for example 30s or 1m"
+	var metricsFileShort string = "read usage from this file of PodMetricsList documents instead of the metrics server, each document is a sample"
+	var patchDirShort string = "write a strategic merge patch with the recommended values for each Deployment, StatefulSet and DaemonSet to this directory"
 	var treeShort string = "Display tree like view instead of the standard list"
 	var nodetreeShort string = "Displays the tree with the nodes as the root"
 	var showIPShort string = "Show the pods IP address column"
@@ -61,6 +68,28 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	KubernetesConfigFlags := genericclioptions.NewConfigFlags(false)
 	rootCmd.SetHelpTemplate(helpTemplate)
 
+	// affinity
+	var cmdAffinity = &cobra.Command{
+		Use:     "affinity",
+		Short:   affinityShort,
+		Long:    fmt.Sprintf("%s\n\n%s", affinityShort, affinityDescription),
+		Example: fmt.Sprintf(affinityExample, rootCmd.CommandPath()),
+		Aliases: []string{"tolerations", "spread"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Affinity(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdAffinity.Flags())
+	cmdAffinity.Flags().BoolP("tree", "t", false, treeShort)
+	cmdAffinity.Flags().BoolP("node-tree", "", false, nodetreeShort)
+	addCommonFlags(cmdAffinity)
+	rootCmd.AddCommand(cmdAffinity)
+
 	// capabilities
 	var cmdCapabilities = &cobra.Command{
 		Use:     "capabilities",
@@ -78,6 +107,8 @@ func InitSubCommands(rootCmd *cobra.Command) {
 		},
 	}
 	KubernetesConfigFlags.AddFlags(cmdCapabilities.Flags())
+	cmdCapabilities.Flags().BoolP("effective", "", false, "show the effective capabilities after the runtime defaults, add and drop lists are applied")
+	cmdCapabilities.Flags().String("default-caps", strings.Join(capabilitiesContainerdDefault, ","), "comma seperated list of the default capabilities given to containers by the runtime")
 	cmdCapabilities.Flags().BoolP("tree", "t", false, treeShort)
 	cmdCapabilities.Flags().BoolP("node-tree", "", false, nodetreeShort)
 	addCommonFlags(cmdCapabilities)
@@ -100,11 +131,34 @@ func InitSubCommands(rootCmd *cobra.Command) {
 		},
 	}
 	KubernetesConfigFlags.AddFlags(cmdCommands.Flags())
+	cmdCommands.Flags().BoolP("expand", "", false, "expand $(VAR) references in the command and arguments using the containers environment")
 	cmdCommands.Flags().BoolP("tree", "t", false, treeShort)
 	cmdCommands.Flags().BoolP("node-tree", "", false, nodetreeShort)
 	addCommonFlags(cmdCommands)
 	rootCmd.AddCommand(cmdCommands)
 
+	// conditions
+	var cmdConditions = &cobra.Command{
+		Use:     "conditions",
+		Short:   conditionsShort,
+		Long:    fmt.Sprintf("%s\n\n%s", conditionsShort, conditionsDescription),
+		Example: fmt.Sprintf(conditionsExample, rootCmd.CommandPath()),
+		Aliases: []string{"cond"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Conditions(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdConditions.Flags())
+	cmdConditions.Flags().BoolP("tree", "t", false, treeShort)
+	cmdConditions.Flags().BoolP("node-tree", "", false, nodetreeShort)
+	addCommonFlags(cmdConditions)
+	rootCmd.AddCommand(cmdConditions)
+
 	// cpu
 	var cmdCPU = &cobra.Command{
 		Use:     "cpu",
@@ -124,6 +178,13 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	cmdCPU.Flags().BoolP("include-init", "i", false, includeInitShort)
 	cmdCPU.Flags().BoolP("oddities", "", false, odditiesShort)
 	cmdCPU.Flags().BoolP("raw", "r", false, "show raw values")
+	cmdCPU.Flags().BoolP("recommend", "", false, recommendShort)
+	cmdCPU.Flags().Int("request-headroom", 15, requestHeadroomShort)
+	cmdCPU.Flags().Int("limit-headroom", 50, limitHeadroomShort)
+	cmdCPU.Flags().Int("samples", 1, samplesShort)
+	cmdCPU.Flags().String("interval", "30s", intervalShort)
+	cmdCPU.Flags().String("metrics-file", "", metricsFileShort)
+	cmdCPU.Flags().String("patch-dir", "", patchDirShort)
 	cmdCPU.Flags().BoolP("tree", "t", false, treeShort)
 	cmdCPU.Flags().BoolP("node-tree", "", false, nodetreeShort)
 	addCommonFlags(cmdCPU)
@@ -152,6 +213,29 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	addCommonFlags(cmdEnvironment)
 	rootCmd.AddCommand(cmdEnvironment)
 
+	// eviction
+	var cmdEviction = &cobra.Command{
+		Use:     "eviction",
+		Short:   evictionShort,
+		Long:    fmt.Sprintf("%s\n\n%s", evictionShort, evictionDescription),
+		Example: fmt.Sprintf(evictionExample, rootCmd.CommandPath()),
+		Aliases: []string{"evict"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Eviction(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdEviction.Flags())
+	cmdEviction.Flags().String("size", "Mi", sizeShort)
+	cmdEviction.Flags().BoolP("tree", "t", false, treeShort)
+	cmdEviction.Flags().BoolP("node-tree", "", false, nodetreeShort)
+	addCommonFlags(cmdEviction)
+	rootCmd.AddCommand(cmdEviction)
+
 	// ip
 	var cmdIP = &cobra.Command{
 		Use:     "ip",
@@ -189,6 +273,8 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	}
 	KubernetesConfigFlags.AddFlags(cmdImage.Flags())
 	cmdImage.Flags().BoolP("id", "", false, "Show running containers id")
+	cmdImage.Flags().BoolP("unique", "", false, "List each image only once along with the number of containers and pods using it")
+	cmdImage.Flags().String("policy", "", "Check each image against the rules in the named yaml policy file")
 	cmdImage.Flags().BoolP("tree", "t", false, treeShort)
 	cmdImage.Flags().BoolP("node-tree", "", false, nodetreeShort)
 	addCommonFlags(cmdImage)
@@ -211,6 +297,7 @@ func InitSubCommands(rootCmd *cobra.Command) {
 		},
 	}
 	KubernetesConfigFlags.AddFlags(cmdLifecycle.Flags())
+	cmdLifecycle.Flags().Int64("drain-seconds", 0, "expected number of seconds a container takes to drain its connections once the preStop hook completes")
 	cmdLifecycle.Flags().BoolP("tree", "t", false, treeShort)
 	cmdLifecycle.Flags().BoolP("node-tree", "", false, nodetreeShort)
 	addCommonFlags(cmdLifecycle)
@@ -237,6 +324,13 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	cmdMemory.Flags().BoolP("include-init", "i", false, includeInitShort)
 	cmdMemory.Flags().BoolP("oddities", "", false, odditiesShort)
 	cmdMemory.Flags().BoolP("raw", "r", false, "show raw values")
+	cmdMemory.Flags().BoolP("recommend", "", false, recommendShort)
+	cmdMemory.Flags().Int("request-headroom", 15, requestHeadroomShort)
+	cmdMemory.Flags().Int("limit-headroom", 50, limitHeadroomShort)
+	cmdMemory.Flags().Int("samples", 1, samplesShort)
+	cmdMemory.Flags().String("interval", "30s", intervalShort)
+	cmdMemory.Flags().String("metrics-file", "", metricsFileShort)
+	cmdMemory.Flags().String("patch-dir", "", patchDirShort)
 	cmdMemory.Flags().String("size", "Mi", sizeShort)
 	cmdMemory.Flags().BoolP("tree", "t", false, treeShort)
 	cmdMemory.Flags().BoolP("node-tree", "", false, nodetreeShort)
@@ -283,6 +377,11 @@ func InitSubCommands(rootCmd *cobra.Command) {
 		},
 	}
 	KubernetesConfigFlags.AddFlags(cmdProbes.Flags())
+	cmdProbes.Flags().BoolP("analyze", "", false, "show worst case probe timings and warn about common probe mistakes")
+	cmdProbes.Flags().BoolP("failures", "", false, "show the number of probe failures recorded in the pod events")
+	cmdProbes.Flags().BoolP("run", "", false, "run each probe once and show the result, requires --yes")
+	cmdProbes.Flags().BoolP("yes", "", false, "confirm probes can be run inside the containers when using --run")
+	cmdProbes.Flags().Int32("startup-seconds", probeDefaultStartTime, "typical number of seconds a container takes to start, used by --analyze")
 	cmdProbes.Flags().BoolP("tree", "t", false, treeShort)
 	cmdProbes.Flags().BoolP("node-tree", "", false, nodetreeShort)
 	addCommonFlags(cmdProbes)
@@ -307,11 +406,34 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	}
 	KubernetesConfigFlags.AddFlags(cmdRestart.Flags())
 	cmdRestart.Flags().BoolP("oddities", "", false, odditiesShort)
+	cmdRestart.Flags().String("since", "", "only show containers that restarted within this duration, for example 30m or 1h")
 	cmdRestart.Flags().BoolP("tree", "t", false, treeShort)
 	cmdRestart.Flags().BoolP("node-tree", "", false, nodetreeShort)
 	addCommonFlags(cmdRestart)
 	rootCmd.AddCommand(cmdRestart)
 
+	// scheduling
+	var cmdScheduling = &cobra.Command{
+		Use:     "scheduling",
+		Short:   schedulingShort,
+		Long:    fmt.Sprintf("%s\n\n%s", schedulingShort, schedulingDescription),
+		Example: fmt.Sprintf(schedulingExample, rootCmd.CommandPath()),
+		Aliases: []string{"sched", "why"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Scheduling(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdScheduling.Flags())
+	cmdScheduling.Flags().BoolP("tree", "t", false, treeShort)
+	cmdScheduling.Flags().BoolP("node-tree", "", false, nodetreeShort)
+	addCommonFlags(cmdScheduling)
+	rootCmd.AddCommand(cmdScheduling)
+
 	// security
 	var cmdSecurity = &cobra.Command{
 		Use:     "security",
@@ -330,11 +452,37 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	}
 	KubernetesConfigFlags.AddFlags(cmdSecurity.Flags())
 	cmdSecurity.Flags().BoolP("selinux", "", false, "show the SELinux context thats applied to the containers")
+	cmdSecurity.Flags().BoolP("effective", "", false, "show pod only settings and where each inherited setting came from")
+	cmdSecurity.Flags().BoolP("isolation", "", false, "show host namespace, seccomp, AppArmor, procMount and hostProcess settings")
+	cmdSecurity.Flags().String("pss", "", "evaluate containers against the named pod security standard, one of baseline or restricted")
 	cmdSecurity.Flags().BoolP("tree", "t", false, treeShort)
 	cmdSecurity.Flags().BoolP("node-tree", "", false, nodetreeShort)
 	addCommonFlags(cmdSecurity)
 	rootCmd.AddCommand(cmdSecurity)
 
+	// startup
+	var cmdStartup = &cobra.Command{
+		Use:     "startup",
+		Short:   startupShort,
+		Long:    fmt.Sprintf("%s\n\n%s", startupShort, startupDescription),
+		Example: fmt.Sprintf(startupExample, rootCmd.CommandPath()),
+		Aliases: []string{"timeline"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Startup(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdStartup.Flags())
+	cmdStartup.Flags().BoolP("gantt", "", false, "add a chart of the startup phases to the output")
+	cmdStartup.Flags().BoolP("tree", "t", false, treeShort)
+	cmdStartup.Flags().BoolP("node-tree", "", false, nodetreeShort)
+	addCommonFlags(cmdStartup)
+	rootCmd.AddCommand(cmdStartup)
+
 	// status
 	var cmdStatus = &cobra.Command{
 		Use:     "status",
@@ -358,6 +506,7 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	cmdStatus.Flags().BoolP("details", "d", false, `Display the timestamp instead of age along with the message column`)
 	cmdStatus.Flags().BoolP("oddities", "", false, odditiesShort)
 	cmdStatus.Flags().BoolP("previous", "p", false, "Show previous state")
+	cmdStatus.Flags().BoolP("history", "", false, "Show the current state along side the last terminated state")
 	cmdStatus.Flags().BoolP("id", "", false, "Show running containers id")
 	cmdStatus.Flags().BoolP("tree", "t", false, treeShort)
 	cmdStatus.Flags().BoolP("node-tree", "", false, nodetreeShort)
@@ -365,6 +514,29 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	addCommonFlags(cmdStatus)
 	rootCmd.AddCommand(cmdStatus)
 
+	// terminations
+	var cmdTerminations = &cobra.Command{
+		Use:     "terminations",
+		Short:   terminationsShort,
+		Long:    fmt.Sprintf("%s\n\n%s", terminationsShort, terminationsDescription),
+		Example: fmt.Sprintf(terminationsExample, rootCmd.CommandPath()),
+		Aliases: []string{"oom", "terminated"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Terminations(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdTerminations.Flags())
+	cmdTerminations.Flags().String("size", "Mi", sizeShort)
+	cmdTerminations.Flags().BoolP("tree", "t", false, treeShort)
+	cmdTerminations.Flags().BoolP("node-tree", "", false, nodetreeShort)
+	addCommonFlags(cmdTerminations)
+	rootCmd.AddCommand(cmdTerminations)
+
 	// version
 	var cmdVersion = &cobra.Command{
 		Use:   "version",
@@ -394,6 +566,8 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	}
 	KubernetesConfigFlags.AddFlags(cmdVolume.Flags())
 	cmdVolume.Flags().BoolP("device", "d", false, "show raw block device mappings within a container")
+	cmdVolume.Flags().BoolP("claims", "", false, "follow persistent volume claims to their bound volume and storage class")
+	cmdVolume.Flags().BoolP("detail", "", false, "show mount details including subPath, propagation, file modes and projected keys")
 	cmdVolume.Flags().BoolP("tree", "t", false, treeShort)
 	cmdVolume.Flags().BoolP("node-tree", "", false, nodetreeShort)
 	addCommonFlags(cmdVolume)
@@ -414,7 +588,7 @@ func addCommonFlags(cmdObj *cobra.Command) {
 	cmdObj.Flags().BoolP("show-namespace", "", false, `Show the namespace column`)
 	cmdObj.Flags().BoolP("show-node", "", false, `Show the node name column`)
 	cmdObj.Flags().BoolP("show-type", "T", false, `Show the container type column, where:
-    I=init container, C=container, E=ephemerial container, P=Pod, D=Deployment, R=ReplicaSet, A=DaemonSet, S=StatefulSet, N=Node`)
+    I=init container, K=sidecar container, C=container, E=ephemerial container, P=Pod, D=Deployment, R=ReplicaSet, A=DaemonSet, S=StatefulSet, N=Node`)
 	cmdObj.Flags().StringP("node-label", "", "", `Show the selected node label as a column`)
 	cmdObj.Flags().StringP("pod-label", "", "", `Show the selected pod label as a column`)
 	cmdObj.Flags().StringP("annotation", "", "", `Show the selected annotation as a column`)
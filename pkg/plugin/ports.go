@@ -14,7 +14,7 @@ var portsDescription = ` Print a details of service ports exposed by containers
 name, port number and protocol type. Port name and host port are only show if avaliable. If no
 name is specified the container port details of all pods in the current namespace are shown.
 
-The T column in the table output denotes S for Standard and I for init containers`
+The T column in the table output denotes S for Standard, I for init and K for sidecar containers`
 
 var portsExample = `  # List containers port info from pods
   %[1]s ports
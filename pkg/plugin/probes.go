@@ -1,11 +1,17 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -14,7 +20,28 @@ var probesShort = "Shows details of configured startup, readiness and liveness p
 var probesDescription = ` Prints details of the currently configured startup, liveness and readiness probes for each 
 container. Details like the delay timeout and action are printed along with the configured probe
 type. If no name is specified the container probe details of all pods in the current namespace
-are shown.`
+are shown.
+
+Use --analyze to interpret the probe settings, the WORST-CASE column shows the number of seconds
+before the probe acts: for startup probes its the startup window (delay + failure x period), for
+liveness probes its the time before a failing container is restarted (failure x period) and for
+readiness probes its the worst case time to ready, including any startup window. GRACE shows the
+probe level terminationGracePeriodSeconds and WARNINGS lists common mistakes:
+
+  same-as-readiness   liveness probe uses the same check as the readiness probe
+  no-readiness        liveness probe is set without a readiness probe
+  timeout>=period     probe timeout is greater than or equal to the period
+  delay<startup       liveness delay is shorter than --startup-seconds and there is no startup probe
+  undeclared-port     probe uses a port the container doesnt declare
+
+Use --failures to read the Unhealthy and ProbeWarning events of each pod, the number of failures
+along with the age and message of the last failure are shown against the probe that failed.
+
+Use --run to run each probe once and show the RESULT, LATENCY and OUTPUT of the check, exec probes
+are run inside the container and HTTPGet probes are sent through the api server proxy, TCPSocket and
+GRPC probes use a short lived port-forward. HTTPGet and TCPSocket probes that set a host are shown
+as an Error as the kubelet connects to that host instead of the pod. As this runs commands inside
+the containers --yes must also be given to confirm.`
 
 var probesExample = `  # List containers probe info from pods
   %[1]s probes
@@ -41,7 +68,17 @@ var probesExample = `  # List containers probe info from pods
   %[1]s probes -l app=web
 
   # List container probe info from all pods where the pod label app is either web or mail
-  %[1]s probes -l "app in (web,mail)"`
+  %[1]s probes -l "app in (web,mail)"
+
+  # Analyze probe timings and list common mistakes for all containers, assuming containers take
+  # 60 seconds to start
+  %[1]s probes --analyze --startup-seconds 60
+
+  # List container probes along with how often each probe has failed
+  %[1]s probes --failures
+
+  # Run each probe of the containers in the pod named web-pod once to check they pass
+  %[1]s probes web-pod --run --yes`
 
 type probeAction struct {
 	probeName  string
@@ -83,6 +120,51 @@ func Probes(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args [
 	builder.Table = &table
 	builder.ShowTreeView = commonFlagList.showTreeView
 
+	if cmd.Flag("analyze").Value.String() == "true" {
+		log.Debug("loopinfo.ShowAnalysis = true")
+		loopinfo.ShowAnalysis = true
+	}
+
+	if cmd.Flag("failures").Value.String() == "true" {
+		log.Debug("loopinfo.ShowFailures = true")
+		loopinfo.ShowFailures = true
+
+		// events can only be read from a live cluster
+		stdinChanged, err := builder.HasStdinChanged()
+		if err != nil {
+			return err
+		}
+		if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
+			loopinfo.Connection = &connect
+		}
+	}
+
+	if cmd.Flag("run").Value.String() == "true" {
+		log.Debug("loopinfo.runner set")
+		if cmd.Flag("yes").Value.String() != "true" {
+			return errors.New("--run executes each probe inside the running containers, add --yes to confirm")
+		}
+
+		stdinChanged, err := builder.HasStdinChanged()
+		if err != nil {
+			return err
+		}
+		if len(commonFlagList.inputFilename) > 0 || stdinChanged {
+			return errors.New("--run can only be used with pods running in a cluster")
+		}
+
+		loopinfo.runner, err = newProbeRunner(connect.restConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	startupSeconds, err := strconv.Atoi(cmd.Flag("startup-seconds").Value.String())
+	if err != nil {
+		return err
+	}
+	loopinfo.StartupSeconds = int32(startupSeconds)
+
 	if err := builder.Build(&loopinfo); err != nil {
 		return err
 	}
@@ -97,8 +179,35 @@ func Probes(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args [
 }
 
 type probes struct {
+	Connection     *Connector
+	ShowAnalysis   bool
+	ShowFailures   bool
+	StartupSeconds int32
+	failures       map[string]probeFailure
+	failuresPod    string
+	runner         *probeRunner
+}
+
+type probeFailure struct {
+	count   int32
+	last    time.Time
+	message string
 }
 
+type probeAnalysis struct {
+	worstCase int32
+	warnings  []string
+}
+
+// default values applied by the api server when the probe fields are left empty
+const (
+	probeDefaultPeriod    = 10
+	probeDefaultTimeout   = 1
+	probeDefaultSuccess   = 1
+	probeDefaultFailure   = 3
+	probeDefaultStartTime = 30
+)
+
 func (s *probes) Headers() []string {
 	return []string{
 		"PROBE",
@@ -109,6 +218,15 @@ func (s *probes) Headers() []string {
 		"FAILURE",
 		"CHECK",
 		"ACTION",
+		"WORST-CASE",
+		"GRACE",
+		"WARNINGS",
+		"FAILURES",
+		"LAST-FAILURE",
+		"LAST-MESSAGE",
+		"RESULT",
+		"LATENCY",
+		"OUTPUT",
 	}
 }
 
@@ -121,19 +239,27 @@ func (s *probes) BuildEphemeralContainerStatus(container v1.ContainerStatus, inf
 }
 
 func (s *probes) HideColumns(info BuilderInformation) []int {
-	return []int{}
+	var hideColumns []int
+
+	if !s.ShowAnalysis {
+		hideColumns = append(hideColumns, 8, 9, 10)
+	}
+
+	if !s.ShowFailures {
+		hideColumns = append(hideColumns, 11, 12, 13)
+	}
+
+	if s.runner == nil {
+		hideColumns = append(hideColumns, 14, 15, 16)
+	}
+
+	return hideColumns
 }
 
 func (s *probes) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	out := []Cell{
-		NewCellText(""),
-		NewCellText(""),
-		NewCellText(""),
-		NewCellText(""),
-		NewCellText(""),
-		NewCellText(""),
-		NewCellText(""),
-		NewCellText(""),
+	out := make([]Cell, len(s.Headers()))
+	for i := range out {
+		out[i] = NewCellText("")
 	}
 	return out, nil
 }
@@ -141,14 +267,48 @@ func (s *probes) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, er
 func (s *probes) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
 	out := [][]Cell{}
 	probeList := s.buildProbeList(container)
-	for _, probe := range probeList {
-		for _, action := range probe {
-			out = append(out, s.probesBuildRow(info, action))
+
+	var analysis map[string]probeAnalysis
+	if s.ShowAnalysis {
+		analysis = analyzeProbes(container, s.StartupSeconds)
+	}
+
+	// always output the probes in the same order
+	for _, name := range []string{"liveness", "readiness", "startup"} {
+		for _, action := range probeList[name] {
+			row := s.probesBuildRow(info, action)
+			row = append(row, s.analysisBuildCells(action, analysis[name])...)
+			row = append(row, s.failureBuildCells(info, name)...)
+			row = append(row, s.runBuildCells(info, container, action)...)
+			out = append(out, row)
 		}
 	}
 	return out, nil
 }
 
+// analysisBuildCells returns the WORST-CASE, GRACE and WARNINGS cells for the probe
+func (s *probes) analysisBuildCells(action probeAction, analysis probeAnalysis) []Cell {
+	if !s.ShowAnalysis {
+		return []Cell{{}, {}, {}}
+	}
+
+	grace := NewCellText("")
+	if action.probe.TerminationGracePeriodSeconds != nil {
+		grace = NewCellInt(fmt.Sprintf("%d", *action.probe.TerminationGracePeriodSeconds), *action.probe.TerminationGracePeriodSeconds)
+	}
+
+	warnings := NewCellText("")
+	if len(analysis.warnings) > 0 {
+		warnings = NewCellColourText(colourWarn, strings.Join(analysis.warnings, ","))
+	}
+
+	return []Cell{
+		NewCellInt(fmt.Sprintf("%d", analysis.worstCase), int64(analysis.worstCase)),
+		grace,
+		warnings,
+	}
+}
+
 func (s *probes) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
 	out := [][]Cell{}
 	return out, nil
@@ -231,7 +391,7 @@ func (s *probes) buildProbeAction(name string, probe *v1.Probe) []probeAction {
 	// translate GRPC action
 	if probe.GRPC != nil {
 		item.actionName = "GRPC"
-		if probe.GRPC.Service == nil {
+		if probe.GRPC.Service != nil {
 			item.action = *probe.GRPC.Service
 		}
 		if probe.GRPC.Port > 0 {
@@ -259,3 +419,232 @@ func (s *probes) buildProbeAction(name string, probe *v1.Probe) []probeAction {
 func (s *probes) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
 	return [][]Cell{}, nil
 }
+
+// failureBuildCells returns the FAILURES, LAST-FAILURE and LAST-MESSAGE cells for the probe
+func (s *probes) failureBuildCells(info BuilderInformation, probeName string) []Cell {
+	if !s.ShowFailures || s.Connection == nil {
+		return []Cell{{}, {}, {}}
+	}
+
+	// the events are summarised once per pod and reused for each container
+	podID := info.Namespace + "/" + info.PodName
+	if s.failuresPod != podID {
+		s.failures = probeFailureSummary(s.Connection.GetPodEvents(info.Data.pod))
+		s.failuresPod = podID
+	}
+
+	failure, ok := s.failures[info.Name+"/"+probeName]
+	if !ok {
+		return []Cell{NewCellInt("0", 0), {}, {}}
+	}
+
+	lastFailure := ""
+	if !failure.last.IsZero() {
+		lastFailure = duration.HumanDuration(time.Since(failure.last))
+	}
+
+	return []Cell{
+		NewCellColourInt(colourBad, fmt.Sprintf("%d", failure.count), int64(failure.count)),
+		NewCellText(lastFailure),
+		NewCellText(failure.message),
+	}
+}
+
+// runBuildCells runs the probe action once and returns the RESULT, LATENCY and OUTPUT cells
+func (s *probes) runBuildCells(info BuilderInformation, container v1.Container, action probeAction) []Cell {
+	if s.runner == nil {
+		return []Cell{{}, {}, {}}
+	}
+
+	// only the action being shown is run, the other actions are removed from the probe
+	probe := action.probe.DeepCopy()
+	probe.ProbeHandler = v1.ProbeHandler{}
+	switch action.actionName {
+	case "Exec":
+		probe.Exec = action.probe.Exec
+	case "HTTPGet":
+		probe.HTTPGet = action.probe.HTTPGet
+	case "GRPC":
+		probe.GRPC = action.probe.GRPC
+	case "TCPSocket":
+		probe.TCPSocket = action.probe.TCPSocket
+	}
+
+	result := s.runner.run(info.Data.pod, container, probe)
+
+	colour := colourWarn
+	switch result.result {
+	case probeResultSuccess:
+		colour = colourOk
+	case probeResultFailure:
+		colour = colourBad
+	}
+
+	latency := result.latency.Milliseconds()
+	return []Cell{
+		NewCellColourText(colour, result.result),
+		NewCellInt(fmt.Sprintf("%dms", latency), latency),
+		NewCellText(result.output),
+	}
+}
+
+// probeFailureSummary counts the Unhealthy and ProbeWarning events, the returned map is keyed on
+// container name and probe name seperated by a /
+func probeFailureSummary(events []v1.Event) map[string]probeFailure {
+	summary := make(map[string]probeFailure)
+
+	for _, event := range events {
+		if event.Reason != "Unhealthy" && event.Reason != "ProbeWarning" {
+			continue
+		}
+
+		containerName := eventContainerName(event)
+		if len(containerName) == 0 {
+			continue
+		}
+
+		// message is in the format "Liveness probe failed: ..."
+		probeName := strings.ToLower(strings.SplitN(event.Message, " ", 2)[0])
+		switch probeName {
+		case "liveness", "readiness", "startup":
+		default:
+			continue
+		}
+
+		count := event.Count
+		last := event.LastTimestamp.Time
+		if event.Series != nil {
+			count = event.Series.Count
+			last = event.Series.LastObservedTime.Time
+		}
+		if count <= 0 {
+			count = 1
+		}
+		if last.IsZero() {
+			last = event.EventTime.Time
+		}
+		if last.IsZero() {
+			last = event.FirstTimestamp.Time
+		}
+
+		id := containerName + "/" + probeName
+		failure := summary[id]
+		failure.count += count
+		if !last.Before(failure.last) {
+			failure.last = last
+			failure.message = strings.Join(strings.Fields(event.Message), " ")
+		}
+		summary[id] = failure
+	}
+
+	return summary
+}
+
+// probeTimings returns the delay, period, timeout, success and failure values of the probe with
+// the api server defaults applied to any empty fields
+func probeTimings(probe *v1.Probe) (int32, int32, int32, int32, int32) {
+	period := probe.PeriodSeconds
+	if period <= 0 {
+		period = probeDefaultPeriod
+	}
+
+	timeout := probe.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = probeDefaultTimeout
+	}
+
+	success := probe.SuccessThreshold
+	if success <= 0 {
+		success = probeDefaultSuccess
+	}
+
+	failure := probe.FailureThreshold
+	if failure <= 0 {
+		failure = probeDefaultFailure
+	}
+
+	return probe.InitialDelaySeconds, period, timeout, success, failure
+}
+
+// analyzeProbes works out the worst case timings of each probe and checks the probes for common
+// mistakes, the returned map is keyed on probe name
+func analyzeProbes(container v1.Container, startupSeconds int32) map[string]probeAnalysis {
+	analysis := make(map[string]probeAnalysis)
+
+	var startupWindow int32
+	if probe := container.StartupProbe; probe != nil {
+		delay, period, _, _, failure := probeTimings(probe)
+		startupWindow = delay + failure*period
+		analysis["startup"] = probeAnalysis{worstCase: startupWindow}
+	}
+
+	if probe := container.LivenessProbe; probe != nil {
+		delay, period, _, _, failure := probeTimings(probe)
+		result := probeAnalysis{worstCase: failure * period}
+
+		if container.ReadinessProbe == nil {
+			result.warnings = append(result.warnings, "no-readiness")
+		} else if equality.Semantic.DeepEqual(probe.ProbeHandler, container.ReadinessProbe.ProbeHandler) {
+			result.warnings = append(result.warnings, "same-as-readiness")
+		}
+
+		if container.StartupProbe == nil && delay < startupSeconds {
+			result.warnings = append(result.warnings, "delay<startup")
+		}
+
+		analysis["liveness"] = result
+	}
+
+	if probe := container.ReadinessProbe; probe != nil {
+		delay, period, _, success, _ := probeTimings(probe)
+		analysis["readiness"] = probeAnalysis{worstCase: startupWindow + delay + success*period}
+	}
+
+	// checks that apply to every probe
+	for name, probe := range map[string]*v1.Probe{"liveness": container.LivenessProbe, "readiness": container.ReadinessProbe, "startup": container.StartupProbe} {
+		if probe == nil {
+			continue
+		}
+
+		result := analysis[name]
+		_, period, timeout, _, _ := probeTimings(probe)
+		if timeout >= period {
+			result.warnings = append(result.warnings, "timeout>=period")
+		}
+
+		if !probePortDeclared(container, probe) {
+			result.warnings = append(result.warnings, "undeclared-port")
+		}
+		analysis[name] = result
+	}
+
+	return analysis
+}
+
+// probePortDeclared checks the port used by the probe is listed in the containers ports, probes that
+// dont use a port always return true
+func probePortDeclared(container v1.Container, probe *v1.Probe) bool {
+	var port intstr.IntOrString
+
+	switch {
+	case probe.HTTPGet != nil:
+		port = probe.HTTPGet.Port
+	case probe.TCPSocket != nil:
+		port = probe.TCPSocket.Port
+	case probe.GRPC != nil:
+		port = intstr.FromInt32(probe.GRPC.Port)
+	default:
+		return true
+	}
+
+	for _, containerPort := range container.Ports {
+		if port.Type == intstr.String && containerPort.Name == port.StrVal {
+			return true
+		}
+		if port.Type == intstr.Int && containerPort.ContainerPort == port.IntVal {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,353 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// max number of characters of probe output to keep
+const probeMaxOutput = 60
+
+// how long to wait for a forwarded tcp connection to be closed by the remote end
+const probeTCPWait = 200 * time.Millisecond
+
+const (
+	probeResultSuccess = "Success"
+	probeResultFailure = "Failure"
+	probeResultError   = "Error"
+)
+
+// probeRunner runs a probe once against a running container, exec probes use the pod exec
+// subresource, http probes use the api server proxy and tcp and grpc probes use a port-forward
+type probeRunner struct {
+	config     *rest.Config
+	clientSet  kubernetes.Interface
+	httpClient *http.Client
+}
+
+type probeResult struct {
+	result  string
+	latency time.Duration
+	output  string
+}
+
+func newProbeRunner(config *rest.Config) (*probeRunner, error) {
+	clientSet, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http client: %w", err)
+	}
+
+	return &probeRunner{
+		config:     config,
+		clientSet:  clientSet,
+		httpClient: httpClient,
+	}, nil
+}
+
+// run executes the probe once using the probes timeout
+func (r *probeRunner) run(pod v1.Pod, container v1.Container, probe *v1.Probe) probeResult {
+	log := logger{location: "probeRunner:run"}
+	log.Debug("Start")
+
+	_, _, timeout, _, _ := probeTimings(probe)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	var result probeResult
+
+	switch {
+	case probe.Exec != nil:
+		result = r.runExec(ctx, pod, container.Name, probe.Exec.Command)
+	case probe.HTTPGet != nil:
+		result = r.runHTTPGet(ctx, pod, container, probe.HTTPGet)
+	case probe.TCPSocket != nil:
+		result = r.runTCPSocket(ctx, pod, container, probe.TCPSocket)
+	case probe.GRPC != nil:
+		result = r.runGRPC(ctx, pod, probe.GRPC)
+	default:
+		result = probeResult{result: probeResultError, output: "unknown probe action"}
+	}
+	result.latency = time.Since(start)
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && result.result != probeResultSuccess {
+		result.result = probeResultFailure
+		result.output = fmt.Sprintf("timeout after %ds", timeout)
+	}
+
+	log.Debug("result", result.result, result.output)
+	return result
+}
+
+// runExec runs the command inside the container, a zero exit code is a success
+func (r *probeRunner) runExec(ctx context.Context, pod v1.Pod, containerName string, command []string) probeResult {
+	req := r.clientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: containerName,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(r.config, "POST", req.URL())
+	if err != nil {
+		return probeResult{result: probeResultError, output: err.Error()}
+	}
+
+	var output bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &output,
+		Stderr: &output,
+	})
+
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+		return probeResult{result: probeResultSuccess, output: probeOutput(output.String())}
+	case errors.As(err, &exitErr):
+		return probeResult{result: probeResultFailure, output: probeOutput(fmt.Sprintf("exit %d: %s", exitErr.ExitStatus(), output.String()))}
+	}
+
+	return probeResult{result: probeResultError, output: probeOutput(err.Error())}
+}
+
+// runHTTPGet requests the path through the api server pod proxy, like the kubelet any status code
+// from 200 to 399 is a success. When a host is set the kubelet connects to that host instead of the
+// pod so the probe cant be run through the proxy
+func (r *probeRunner) runHTTPGet(ctx context.Context, pod v1.Pod, container v1.Container, action *v1.HTTPGetAction) probeResult {
+	if len(action.Host) > 0 {
+		return probeResult{result: probeResultError, output: "host set, can not run through pod proxy"}
+	}
+
+	port, err := probeResolvePort(container, action.Port)
+	if err != nil {
+		return probeResult{result: probeResultError, output: err.Error()}
+	}
+
+	urlScheme := strings.ToLower(string(action.Scheme))
+	if len(urlScheme) == 0 {
+		urlScheme = "http"
+	}
+
+	path, err := url.Parse(action.Path)
+	if err != nil {
+		return probeResult{result: probeResultError, output: err.Error()}
+	}
+
+	req := r.clientSet.CoreV1().RESTClient().Get().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(utilnet.JoinSchemeNamePort(urlScheme, pod.Name, strconv.Itoa(int(port)))).
+		SubResource("proxy").
+		Suffix(path.Path)
+
+	for key, valueList := range path.Query() {
+		for _, value := range valueList {
+			req.Param(key, value)
+		}
+	}
+
+	// the request is made directly so we can read the status code and body of failed requests
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL().String(), nil)
+	if err != nil {
+		return probeResult{result: probeResultError, output: probeOutput(err.Error())}
+	}
+	for _, header := range action.HTTPHeaders {
+		httpReq.Header.Add(header.Name, header.Value)
+	}
+
+	resp, err := r.httpClient.Do(httpReq)
+	if err != nil {
+		return probeResult{result: probeResultError, output: probeOutput(err.Error())}
+	}
+	defer resp.Body.Close()
+
+	statusCode := resp.StatusCode
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, probeMaxOutput*4))
+
+	output := probeOutput(fmt.Sprintf("%d %s", statusCode, body))
+	if statusCode >= http.StatusOK && statusCode < http.StatusBadRequest {
+		return probeResult{result: probeResultSuccess, output: output}
+	}
+
+	return probeResult{result: probeResultFailure, output: output}
+}
+
+// runTCPSocket opens a connection to the port through a port-forward, the connection is a
+// success if the remote end accepts it
+func (r *probeRunner) runTCPSocket(ctx context.Context, pod v1.Pod, container v1.Container, action *v1.TCPSocketAction) probeResult {
+	// like HTTPGet a host means the kubelet doesnt connect to the pod
+	if len(action.Host) > 0 {
+		return probeResult{result: probeResultError, output: "host set, can not run through port-forward"}
+	}
+
+	port, err := probeResolvePort(container, action.Port)
+	if err != nil {
+		return probeResult{result: probeResultError, output: err.Error()}
+	}
+
+	localPort, stop, err := r.portForward(ctx, pod, port)
+	if err != nil {
+		return probeResult{result: probeResultError, output: probeOutput(err.Error())}
+	}
+	defer stop()
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(localPort))))
+	if err != nil {
+		return probeResult{result: probeResultError, output: probeOutput(err.Error())}
+	}
+	defer conn.Close()
+
+	// the local side always connects, the remote connection is only made once the stream is
+	// opened so we wait a short time to see if the forwarder closes the connection on us
+	wait := time.Now().Add(probeTCPWait)
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(wait) {
+		wait = deadline
+	}
+	conn.SetReadDeadline(wait)
+	_, err = conn.Read(make([]byte, 1))
+
+	var netErr net.Error
+	if err == nil || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return probeResult{result: probeResultSuccess, output: fmt.Sprintf("connected to port %d", port)}
+	}
+
+	return probeResult{result: probeResultFailure, output: probeOutput(fmt.Sprintf("port %d closed the connection", port))}
+}
+
+// runGRPC calls the standard grpc health check through a port-forward
+func (r *probeRunner) runGRPC(ctx context.Context, pod v1.Pod, action *v1.GRPCAction) probeResult {
+	localPort, stop, err := r.portForward(ctx, pod, action.Port)
+	if err != nil {
+		return probeResult{result: probeResultError, output: probeOutput(err.Error())}
+	}
+	defer stop()
+
+	conn, err := grpc.NewClient(net.JoinHostPort("127.0.0.1", strconv.Itoa(int(localPort))), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return probeResult{result: probeResultError, output: probeOutput(err.Error())}
+	}
+	defer conn.Close()
+
+	service := ""
+	if action.Service != nil {
+		service = *action.Service
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return probeResult{result: probeResultFailure, output: probeOutput(err.Error())}
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return probeResult{result: probeResultFailure, output: resp.GetStatus().String()}
+	}
+
+	return probeResult{result: probeResultSuccess, output: resp.GetStatus().String()}
+}
+
+// portForward forwards a random local port to the pods port, returns the local port number and a
+// function to stop the forward
+func (r *probeRunner) portForward(ctx context.Context, pod v1.Pod, port int32) (uint16, func(), error) {
+	transport, upgrader, err := spdy.RoundTripperFor(r.config)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req := r.clientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	stopChan := make(chan struct{})
+	readyChan := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, stopChan, readyChan, io.Discard, io.Discard)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- forwarder.ForwardPorts()
+	}()
+
+	stop := func() {
+		close(stopChan)
+	}
+
+	select {
+	case <-readyChan:
+	case err := <-errChan:
+		return 0, nil, err
+	case <-ctx.Done():
+		stop()
+		return 0, nil, ctx.Err()
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		stop()
+		return 0, nil, errors.New("unable to read forwarded port")
+	}
+
+	return ports[0].Local, stop, nil
+}
+
+// probeResolvePort converts a named port into the port number declared by the container
+func probeResolvePort(container v1.Container, port intstr.IntOrString) (int32, error) {
+	if port.Type == intstr.Int {
+		return port.IntVal, nil
+	}
+
+	for _, containerPort := range container.Ports {
+		if containerPort.Name == port.StrVal {
+			return containerPort.ContainerPort, nil
+		}
+	}
+
+	return 0, errors.New("port " + port.StrVal + " not found")
+}
+
+// probeOutput collapses the output onto a single line and trims it to probeMaxOutput characters
+func probeOutput(output string) string {
+	output = strings.Join(strings.Fields(output), " ")
+	if len([]rune(output)) > probeMaxOutput {
+		output = string([]rune(output)[:probeMaxOutput-3]) + "..."
+	}
+	return output
+}
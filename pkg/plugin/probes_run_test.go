@@ -0,0 +1,105 @@
+package plugin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/rest"
+)
+
+// *****************
+// probeRunner
+// *****************
+
+// newFakeProbeServer returns an api server that answers pod proxy requests for the pod web-pod,
+// every request path is recorded in requests, the server is closed when the test finishes
+func newFakeProbeServer(t *testing.T, requests *[]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.Method+" "+r.URL.Path)
+
+		switch r.URL.Path {
+		case "/api/v1/namespaces/default/pods/http:web-pod:8080/proxy/healthz":
+			if r.Header.Get("X-Probe") != "kubelet" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte("ok"))
+		case "/api/v1/namespaces/default/pods/http:web-pod:8080/proxy/redirect":
+			w.WriteHeader(http.StatusFound)
+		case "/api/v1/namespaces/default/pods/https:web-pod:8443/proxy/broken":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("database\nunavailable"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+type probeRunnerTest struct {
+	arg1           *v1.Probe
+	expectedResult string
+	expectedOutput string
+}
+
+var probeRunnerTests = []probeRunnerTest{
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("http"), HTTPHeaders: []v1.HTTPHeader{{Name: "X-Probe", Value: "kubelet"}}}}}, probeResultSuccess, "200 ok"},
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/redirect", Port: intstr.FromInt32(8080)}}}, probeResultSuccess, "302"},
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/broken", Port: intstr.FromInt32(8443), Scheme: v1.URISchemeHTTPS}}}, probeResultFailure, "503 database unavailable"},
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("metrics")}}}, probeResultError, "port metrics not found"},
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Host: "10.0.0.1", Path: "/healthz", Port: intstr.FromString("http")}}}, probeResultError, "host set, can not run through pod proxy"},
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{TCPSocket: &v1.TCPSocketAction{Host: "10.0.0.1", Port: intstr.FromString("http")}}}, probeResultError, "host set, can not run through port-forward"},
+}
+
+func TestProbeRunner(t *testing.T) {
+	var requests []string
+	server := newFakeProbeServer(t, &requests)
+
+	runner, err := newProbeRunner(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-pod", Namespace: "default"}}
+	container := v1.Container{Name: "web", Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}}}
+
+	for _, test := range probeRunnerTests {
+		output := runner.run(pod, container, test.arg1)
+		if output.result != test.expectedResult {
+			t.Errorf("Output %s not equal to expected %s", output.result, test.expectedResult)
+		}
+		if output.output != test.expectedOutput {
+			t.Errorf("Output %s not equal to expected %s", output.output, test.expectedOutput)
+		}
+	}
+}
+
+func TestProbeRunnerExec(t *testing.T) {
+	var requests []string
+	server := newFakeProbeServer(t, &requests)
+
+	runner, err := newProbeRunner(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-pod", Namespace: "default"}}
+	probe := &v1.Probe{ProbeHandler: v1.ProbeHandler{Exec: &v1.ExecAction{Command: []string{"cat", "/tmp/healthy"}}}}
+
+	// the fake server cant upgrade the connection so the probe should error after calling exec
+	output := runner.run(pod, v1.Container{Name: "web"}, probe)
+	if output.result != probeResultError {
+		t.Errorf("Output %s not equal to expected %s", output.result, probeResultError)
+	}
+
+	expected := "POST /api/v1/namespaces/default/pods/web-pod/exec"
+	if len(requests) == 0 || requests[0] != expected {
+		t.Errorf("Output %v not equal to expected %s", requests, expected)
+	}
+}
@@ -0,0 +1,139 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// *****************
+// analyzeProbes
+// *****************
+
+type analyzeProbesTest struct {
+	arg1              v1.Container
+	arg2              string
+	expectedWorstCase int32
+	expectedWarnings  string
+}
+
+var probeHTTPHandler = v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("http")}}
+var probeTCPHandler = v1.ProbeHandler{TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt32(8080)}}
+
+var analyzeProbesTests = []analyzeProbesTest{
+	// defaults are applied to empty fields
+	{v1.Container{
+		Ports:          []v1.ContainerPort{{Name: "http", ContainerPort: 80}},
+		LivenessProbe:  &v1.Probe{ProbeHandler: probeHTTPHandler, InitialDelaySeconds: 30},
+		ReadinessProbe: &v1.Probe{ProbeHandler: probeTCPHandler},
+	}, "liveness", 30, ""},
+	{v1.Container{
+		Ports:          []v1.ContainerPort{{Name: "http", ContainerPort: 80}},
+		LivenessProbe:  &v1.Probe{ProbeHandler: probeHTTPHandler, InitialDelaySeconds: 30},
+		ReadinessProbe: &v1.Probe{ProbeHandler: probeTCPHandler},
+	}, "readiness", 10, "undeclared-port"},
+	{v1.Container{
+		Ports:          []v1.ContainerPort{{Name: "http", ContainerPort: 80}},
+		LivenessProbe:  &v1.Probe{ProbeHandler: probeHTTPHandler, PeriodSeconds: 5, TimeoutSeconds: 5},
+		ReadinessProbe: &v1.Probe{ProbeHandler: probeHTTPHandler, PeriodSeconds: 10},
+	}, "liveness", 15, "same-as-readiness,delay<startup,timeout>=period"},
+	{v1.Container{
+		LivenessProbe: &v1.Probe{ProbeHandler: probeTCPHandler, PeriodSeconds: 10, FailureThreshold: 6},
+		StartupProbe:  &v1.Probe{ProbeHandler: probeTCPHandler, InitialDelaySeconds: 5, PeriodSeconds: 10, FailureThreshold: 30},
+	}, "liveness", 60, "no-readiness,undeclared-port"},
+	{v1.Container{
+		Ports:          []v1.ContainerPort{{ContainerPort: 8080}},
+		ReadinessProbe: &v1.Probe{ProbeHandler: probeTCPHandler, InitialDelaySeconds: 5, PeriodSeconds: 10, SuccessThreshold: 2},
+		StartupProbe:   &v1.Probe{ProbeHandler: probeTCPHandler, PeriodSeconds: 10, FailureThreshold: 30},
+	}, "readiness", 325, ""},
+	{v1.Container{
+		Ports:          []v1.ContainerPort{{ContainerPort: 8080}},
+		ReadinessProbe: &v1.Probe{ProbeHandler: probeTCPHandler, InitialDelaySeconds: 5, PeriodSeconds: 10, SuccessThreshold: 2},
+		StartupProbe:   &v1.Probe{ProbeHandler: probeTCPHandler, PeriodSeconds: 10, FailureThreshold: 30},
+	}, "startup", 300, ""},
+}
+
+func TestAnalyzeProbes(t *testing.T) {
+
+	for _, test := range analyzeProbesTests {
+		output := analyzeProbes(test.arg1, 30)[test.arg2]
+		if output.worstCase != test.expectedWorstCase {
+			t.Errorf("Output %d not equal to expected %d, for %s probe", output.worstCase, test.expectedWorstCase, test.arg2)
+		}
+
+		warnings := strings.Join(output.warnings, ",")
+		if warnings != test.expectedWarnings {
+			t.Errorf("Output %s not equal to expected %s, for %s probe", warnings, test.expectedWarnings, test.arg2)
+		}
+	}
+}
+
+// *****************
+// probeFailureSummary
+// *****************
+
+type probeFailureSummaryTest struct {
+	arg1            string
+	expectedCount   int32
+	expectedMessage string
+}
+
+var probeEventTime = time.Date(2023, 2, 15, 10, 0, 0, 0, time.UTC)
+
+var probeEvents = []v1.Event{
+	{
+		Reason:         "Unhealthy",
+		Message:        "Liveness probe failed: HTTP probe failed with statuscode: 500",
+		InvolvedObject: v1.ObjectReference{FieldPath: "spec.containers{web}"},
+		Count:          4,
+		LastTimestamp:  metav1.NewTime(probeEventTime),
+	},
+	{
+		Reason:         "Unhealthy",
+		Message:        "Liveness probe failed: Get \"http://10.0.0.1:80/\": context deadline exceeded",
+		InvolvedObject: v1.ObjectReference{FieldPath: "spec.containers{web}"},
+		Series:         &v1.EventSeries{Count: 2, LastObservedTime: metav1.NewMicroTime(probeEventTime.Add(time.Minute))},
+	},
+	{
+		Reason:         "Unhealthy",
+		Message:        "Readiness probe errored: rpc error",
+		InvolvedObject: v1.ObjectReference{FieldPath: "spec.initContainers{sidecar}"},
+	},
+	{
+		Reason:         "ProbeWarning",
+		Message:        "Startup probe warning: redirect\\nignored",
+		InvolvedObject: v1.ObjectReference{FieldPath: "spec.containers{web}"},
+		Count:          1,
+	},
+	{
+		Reason:         "Killing",
+		Message:        "Container web failed liveness probe, will be restarted",
+		InvolvedObject: v1.ObjectReference{FieldPath: "spec.containers{web}"},
+		Count:          1,
+	},
+}
+
+var probeFailureSummaryTests = []probeFailureSummaryTest{
+	{"web/liveness", 6, "Liveness probe failed: Get \"http://10.0.0.1:80/\": context deadline exceeded"},
+	{"sidecar/readiness", 1, "Readiness probe errored: rpc error"},
+	{"web/startup", 1, "Startup probe warning: redirect\\nignored"},
+	{"web/readiness", 0, ""},
+}
+
+func TestProbeFailureSummary(t *testing.T) {
+	summary := probeFailureSummary(probeEvents)
+
+	for _, test := range probeFailureSummaryTests {
+		output := summary[test.arg1]
+		if output.count != test.expectedCount {
+			t.Errorf("Output %d not equal to expected %d, for %s", output.count, test.expectedCount, test.arg1)
+		}
+		if output.message != test.expectedMessage {
+			t.Errorf("Output %s not equal to expected %s, for %s", output.message, test.expectedMessage, test.arg1)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
@@ -22,7 +25,22 @@ serve as an easy way to see how close you are to the configured sizes.  By speci
 flag you can see raw unfiltered values.  If no name is specified the container %[1]s details
 of all pods in the current namespace are shown.
 
-The T column in the table output denotes S for Standard and I for init containers`, r)
+ACTUAL-REQUEST and ACTUAL-LIMIT show the %[1]s applied to the running container as reported by the
+kubelet, these differ from the spec when a pod has been resized in place and the RESIZE column
+shows if the resize is Deferred, Infeasible or InProgress. On tree views the pod totals include
+pod level resources and the pod overhead. The QOS column shows the QoS class of the pod.
+
+The --recommend flag adds RECOMMENDED-REQUEST and RECOMMENDED-LIMIT columns, the request is the
+average usage plus --request-headroom percent and the limit is the peak usage plus --limit-headroom
+percent. Usage is read once from the metrics server unless --samples is given, in which case it is
+read that many times waiting --interval between each read. Usage exported from another source can
+be read with --metrics-file. SAVINGS is the current request minus the recommended request.
+
+Using --patch-dir writes a strategic merge patch for each Deployment, StatefulSet and DaemonSet
+with the recommended values so they can be reviewed and applied, the cluster is never changed.
+
+The T column in the table output denotes S for Standard, I for init and K for sidecar containers.
+Sidecar containers are always shown and the pod totals count them the same way the scheduler does`, r)
 }
 
 // returns a string replacing %[2] with the resourse type r
@@ -53,7 +71,13 @@ func resourceExample(r string) string {
   %[1]s %[2]s -l app=web
 
   # List container %[2]s info from all pods where the pod label app is either web or mail
-  %[1]s %[2]s -l "app in (web,mail)"`, "%[1]s", r)
+  %[1]s %[2]s -l "app in (web,mail)"
+
+  # Show recommended %[2]s requests and limits from 10 samples taken a minute apart
+  %[1]s %[2]s --recommend --samples 10 --interval 1m
+
+  # Write patches with the recommended %[2]s for each workload to the patches directory
+  %[1]s %[2]s --recommend --request-headroom 20 --patch-dir ./patches`, "%[1]s", r)
 }
 
 func Resources(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string, resourceType string) error {
@@ -81,15 +105,57 @@ func Resources(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, arg
 	builder.SetFlagsFrom(commonFlagList)
 
 	loopinfo.ResourceType = resourceType
+	loopinfo.FilterContainer = len(commonFlagList.container) > 0 || len(commonFlagList.filterList) > 0
+	loopinfo.ShowInit = commonFlagList.showInitContainers
+	loopinfo.Flags = commonFlagList
 
 	stdinChanged, err := builder.HasStdinChanged()
 	if err != nil {
 		return err
 	}
+	isLive := len(commonFlagList.inputFilename) == 0 && !stdinChanged
+
+	sampleCount := 1
+	sampleInterval := 30 * time.Second
+	metricsFile := ""
+	patchDir := ""
+	if cmd.Flag("recommend") != nil {
+		if cmd.Flag("recommend").Value.String() == "true" {
+			loopinfo.Recommend = true
+		}
+
+		loopinfo.RequestHeadroom, err = strconv.ParseInt(cmd.Flag("request-headroom").Value.String(), 10, 64)
+		if err != nil || loopinfo.RequestHeadroom < 0 {
+			return errors.New("--request-headroom must be a positive percentage")
+		}
+		loopinfo.LimitHeadroom, err = strconv.ParseInt(cmd.Flag("limit-headroom").Value.String(), 10, 64)
+		if err != nil || loopinfo.LimitHeadroom < 0 {
+			return errors.New("--limit-headroom must be a positive percentage")
+		}
+
+		sampleCount, err = strconv.Atoi(cmd.Flag("samples").Value.String())
+		if err != nil || sampleCount < 1 {
+			return errors.New("--samples must be 1 or more")
+		}
+		sampleInterval, err = time.ParseDuration(cmd.Flag("interval").Value.String())
+		if err != nil {
+			return fmt.Errorf("invalid value for --interval: %w", err)
+		}
+
+		metricsFile = cmd.Flag("metrics-file").Value.String()
+		patchDir = cmd.Flag("patch-dir").Value.String()
+		if !loopinfo.Recommend && (len(metricsFile) > 0 || len(patchDir) > 0 || sampleCount > 1) {
+			return errors.New("--samples, --metrics-file and --patch-dir can only be used with --recommend")
+		}
+		if len(patchDir) > 0 && !isLive {
+			return errors.New("--patch-dir needs a live cluster to find the owner of each pod")
+		}
+	}
 
 	//only need to pull metrics info we are reading live data,
 	// if we read from a file metric data wont exist
-	if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
+	samples := [][]v1beta1.PodMetrics{}
+	if isLive {
 		if err := connect.LoadMetricConfig(kubeFlags); err != nil {
 			return err
 		}
@@ -97,9 +163,28 @@ func Resources(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, arg
 		if err != nil {
 			log.Tell(err)
 		} else {
-			loopinfo.MetricsResource = loopinfo.podMetrics2Hashtable(podStateList)
+			loopinfo.MetricsResource = podMetrics2Hashtable(podStateList)
+
+			if loopinfo.Recommend && len(metricsFile) == 0 {
+				samples, err = resourceSampleMetrics(&connect, args, podStateList, sampleCount, sampleInterval)
+				if err != nil {
+					log.Tell(err)
+				}
+			}
+		}
+	}
+
+	if len(metricsFile) > 0 {
+		samples, err = resourceReadMetricsFile(metricsFile)
+		if err != nil {
+			return err
+		}
+		if loopinfo.MetricsResource == nil && len(samples) > 0 {
+			// show the most recent usage when there is no metrics server
+			loopinfo.MetricsResource = podMetrics2Hashtable(samples[len(samples)-1])
 		}
 	}
+	loopinfo.Samples = resourceUsageSamples(samples)
 
 	if cmd.Flag("size") != nil {
 		if len(cmd.Flag("size").Value.String()) > 0 {
@@ -136,6 +221,14 @@ func Resources(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, arg
 		table.HideRows(row2Remove)
 	}
 
+	// patches are only written to disk, the cluster is never changed
+	if len(patchDir) > 0 {
+		patches := loopinfo.resourceBuildPatches(connect.BuildOwnersList())
+		if err := loopinfo.resourceWritePatches(patchDir, patches); err != nil {
+			return err
+		}
+	}
+
 	outputTableAs(table, commonFlagList.outputAs)
 	return nil
 }
@@ -147,11 +240,36 @@ type resource struct {
 	ShowRaw         bool
 	ShowPrevious    bool
 	ShowDetails     bool
+	Recommend       bool
+	RequestHeadroom int64                                   // percentage added to the average usage
+	LimitHeadroom   int64                                   // percentage added to the peak usage
+	Samples         map[string]map[string][]v1.ResourceList // usage samples of each pod and container
+	FilterContainer bool                                    // containers are filtered using -c or --match
+	Flags           commonFlags                             // used to skip containers filtered with -c
+	ShowInit        bool                                    // init containers are listed
+}
+
+// showsAllContainers returns true when every container the scheduler counts is listed under the pod,
+// sidecars are always listed so init containers only matter when they are not shown
+func (s *resource) showsAllContainers(pod v1.Pod) bool {
+	if s.FilterContainer {
+		return false
+	}
+	if s.ShowInit {
+		return true
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if !isSidecarContainer(container) {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *resource) Headers() []string {
 	return []string{
-		"USED", "REQUEST", "LIMIT", "%REQ", "%LIMIT",
+		"USED", "REQUEST", "LIMIT", "%REQ", "%LIMIT", "ACTUAL-REQUEST", "ACTUAL-LIMIT", "RESIZE", "QOS",
+		"RECOMMENDED-REQUEST", "RECOMMENDED-LIMIT", "SAVINGS",
 	}
 }
 
@@ -160,17 +278,59 @@ func (s *resource) BuildContainerStatus(container v1.ContainerStatus, info Build
 }
 
 func (s *resource) HideColumns(info BuilderInformation) []int {
+	if !s.Recommend {
+		// hide RECOMMENDED-REQUEST RECOMMENDED-LIMIT SAVINGS
+		return []int{9, 10, 11}
+	}
 	return []int{}
 }
 
 func (s *resource) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	rowOut := make([]Cell, 5)
+	rowOut := make([]Cell, len(s.Headers()))
 
+	resizeCounts := make(map[string]int64)
+	qosCounts := make(map[string]int64)
 	for _, r := range rows {
-		// "USED", "REQUEST", "LIMIT", "%REQ", "%LIMIT",
+		// "USED", "REQUEST", "LIMIT", "%REQ", "%LIMIT", "ACTUAL-REQUEST", "ACTUAL-LIMIT", "RESIZE", "QOS"
 		rowOut[0].number += r[0].number
 		rowOut[1].number += r[1].number
 		rowOut[2].number += r[2].number
+		rowOut[5].number += r[5].number
+		rowOut[6].number += r[6].number
+		rowOut[9].number += r[9].number
+		rowOut[10].number += r[10].number
+		rowOut[11].number += r[11].number
+		if info.TypeName != TypeNamePod {
+			// pod rows hold a single value while owner rows hold a count of each value
+			addCountList(resizeCounts, r[7].text)
+			addCountList(qosCounts, r[8].text)
+		}
+	}
+
+	if info.TypeName == TypeNamePod {
+		pod := info.Data.pod
+		if s.showsAllContainers(pod) {
+			// sidecar and init containers are counted the same way the scheduler counts them, pod
+			// level resources replace the container totals and the overhead is added on top, when
+			// only some containers are listed the totals are just the sum of the listed rows
+			rowOut[1].number = s.resourceValue(schedulingPodResources(pod, resourceRequests))
+			rowOut[2].number = s.resourceValue(resourcePodLimits(pod))
+
+			rowOut[5].number = 0
+			rowOut[6].number = 0
+			if actualPod, ok := resourceActualPod(pod); ok {
+				rowOut[5].number = s.resourceValue(schedulingPodResources(actualPod, resourceRequests))
+				rowOut[6].number = s.resourceValue(resourcePodLimits(actualPod))
+			}
+		}
+
+		state := resourceResizeStatus(pod)
+		rowOut[7] = NewCellColourText(resourceResizeColour(state), state)
+
+		rowOut[8] = qosCell(pod)
+	} else {
+		rowOut[7] = NewCellText(statusFormatReasonCounts(resizeCounts))
+		rowOut[8] = NewCellText(statusFormatReasonCounts(qosCounts))
 	}
 
 	floatfmt := "%.6f"
@@ -192,6 +352,11 @@ func (s *resource) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell,
 		}
 		rowOut[1].text = memoryHumanReadable(rowOut[1].number, s.BytesAs)
 		rowOut[2].text = memoryHumanReadable(rowOut[2].number, s.BytesAs)
+		for _, i := range []int{5, 6, 9, 10} {
+			if rowOut[i].number > 0 {
+				rowOut[i].text = memoryHumanReadable(rowOut[i].number, s.BytesAs)
+			}
+		}
 	} else {
 		if s.ShowRaw {
 			rowOut[0].text = fmt.Sprintf("%dn", rowOut[0].number)
@@ -200,6 +365,22 @@ func (s *resource) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell,
 		}
 		rowOut[1].text = fmt.Sprintf(typefmt, rowOut[1].number)
 		rowOut[2].text = fmt.Sprintf(typefmt, rowOut[2].number)
+		for _, i := range []int{5, 6, 9, 10} {
+			if rowOut[i].number > 0 {
+				rowOut[i].text = fmt.Sprintf(typefmt, rowOut[i].number)
+			}
+		}
+	}
+
+	if rowOut[9].number > 0 {
+		rowOut[11] = s.savingsCell(rowOut[11].number)
+	}
+
+	// warn when the resources in use do not match the spec
+	for _, i := range []int{5, 6} {
+		if rowOut[i].number > 0 && rowOut[i].number != rowOut[i-4].number {
+			rowOut[i].colour = colourWarn
+		}
 	}
 
 	if rowOut[0].number > 0 {
@@ -232,10 +413,24 @@ func (s *resource) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell,
 	return rowOut, nil
 }
 
+// resourceValue returns the value of the selected resource type in the same units used by the table
+func (s *resource) resourceValue(list v1.ResourceList) int64 {
+	if s.ResourceType == "memory" {
+		return list.Memory().Value()
+	}
+	if s.ShowRaw {
+		return list.Cpu().ScaledValue(apires.Nano)
+	}
+	return list.Cpu().MilliValue()
+}
+
 func (s *resource) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
 	metrics := s.MetricsResource[info.PodName][info.Name]
 	out := make([][]Cell, 1)
 	out[0] = s.statsProcessTableRow(container.Resources, metrics, info, s.ResourceType)
+	out[0] = append(out[0], s.actualCells(container.Resources, info)...)
+	out[0] = append(out[0], qosCell(info.Data.pod))
+	out[0] = append(out[0], s.recommendCells(container.Resources, info)...)
 	return out, nil
 }
 
@@ -243,9 +438,191 @@ func (s *resource) BuildEphemeralContainerSpec(container v1.EphemeralContainer,
 	metrics := s.MetricsResource[info.PodName][info.Name]
 	out := make([][]Cell, 1)
 	out[0] = s.statsProcessTableRow(container.Resources, metrics, info, s.ResourceType)
+	out[0] = append(out[0], s.actualCells(container.Resources, info)...)
+	out[0] = append(out[0], qosCell(info.Data.pod))
+	out[0] = append(out[0], s.recommendCells(container.Resources, info)...)
 	return out, nil
 }
 
+// actualCells returns the ACTUAL-REQUEST, ACTUAL-LIMIT and RESIZE columns, the actual values are
+// the resources the kubelet reports as applied to the running container
+func (s *resource) actualCells(spec v1.ResourceRequirements, info BuilderInformation) []Cell {
+	actual, ok := resourceActual(spec, containerStatus(info))
+	if !ok {
+		return []Cell{NewCellText(""), NewCellText(""), NewCellText("")}
+	}
+
+	// the pod resize state only applies to containers that have not been resized yet
+	state := ""
+	if !resourceListEqual(spec.Requests, actual.Requests) || !resourceListEqual(spec.Limits, actual.Limits) {
+		state = resourceResizeStatus(info.Data.pod)
+	}
+
+	return []Cell{
+		s.resourceCell(actual.Requests, spec.Requests),
+		s.resourceCell(actual.Limits, spec.Limits),
+		NewCellColourText(resourceResizeColour(state), state),
+	}
+}
+
+// resourceCell formats the selected resource type in list the same way as the REQUEST and LIMIT
+// columns, the cell is coloured as a warning when it differs from the spec
+func (s *resource) resourceCell(list v1.ResourceList, spec v1.ResourceList) Cell {
+	name := v1.ResourceName(s.ResourceType)
+	quantity, ok := list[name]
+	if !ok {
+		return NewCellText("")
+	}
+
+	colour := [2]int{-1, 0}
+	if specQuantity, ok := spec[name]; !ok || quantity.Cmp(specQuantity) != 0 {
+		colour = colourWarn
+	}
+
+	value := s.resourceValue(list)
+	text := quantity.String()
+	if s.ResourceType == "cpu" {
+		if s.ShowRaw {
+			text = fmt.Sprintf("%dn", value)
+		} else {
+			text = fmt.Sprintf("%dm", value)
+		}
+	}
+
+	return NewCellColourInt(colour, text, value)
+}
+
+// resourceRequests selects the requests from res
+func resourceRequests(res v1.ResourceRequirements) v1.ResourceList {
+	return res.Requests
+}
+
+// resourceLimits selects the limits from res
+func resourceLimits(res v1.ResourceRequirements) v1.ResourceList {
+	return res.Limits
+}
+
+// resourcePodLimits totals the limits of the pod, the overhead is only added to resources that
+// have a limit as a missing limit means the pod is unbounded
+func resourcePodLimits(pod v1.Pod) v1.ResourceList {
+	withoutOverhead := *pod.DeepCopy()
+	withoutOverhead.Spec.Overhead = nil
+	limits := schedulingPodResources(withoutOverhead, resourceLimits)
+
+	for name, quantity := range pod.Spec.Overhead {
+		if value, ok := limits[name]; ok {
+			value.Add(quantity)
+			limits[name] = value
+		}
+	}
+
+	return limits
+}
+
+// resourceActual returns the resources applied to the running container, the kubelet reports
+// these once the container has started and they differ from the spec while a resize is pending
+// or in progress. Only the allocated requests are known when the full resources are not reported
+func resourceActual(spec v1.ResourceRequirements, status *v1.ContainerStatus) (v1.ResourceRequirements, bool) {
+	if status == nil {
+		return v1.ResourceRequirements{}, false
+	}
+
+	if status.Resources != nil {
+		return *status.Resources, true
+	}
+
+	if status.AllocatedResources != nil {
+		return v1.ResourceRequirements{Requests: status.AllocatedResources, Limits: spec.Limits}, true
+	}
+
+	return v1.ResourceRequirements{}, false
+}
+
+// resourceActualPod returns a copy of the pod with the resources of each container replaced by the
+// actual resources reported in its status, false is returned when no actual resources are known
+func resourceActualPod(pod v1.Pod) (v1.Pod, bool) {
+	actualPod := *pod.DeepCopy()
+	found := false
+
+	replace := func(containers []v1.Container, statusList []v1.ContainerStatus) {
+		for i, container := range containers {
+			for j, status := range statusList {
+				if status.Name != container.Name {
+					continue
+				}
+				if actual, ok := resourceActual(container.Resources, &statusList[j]); ok {
+					containers[i].Resources = actual
+					found = true
+				}
+			}
+		}
+	}
+
+	replace(actualPod.Spec.InitContainers, actualPod.Status.InitContainerStatuses)
+	replace(actualPod.Spec.Containers, actualPod.Status.ContainerStatuses)
+
+	return actualPod, found
+}
+
+// resourceResizeStatus returns the state of an in place resize of the pod, a pending resize is
+// shown by its reason of Deferred or Infeasible as a newer resize replaces one in progress
+func resourceResizeStatus(pod v1.Pod) string {
+	inProgress := ""
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case v1.PodResizePending:
+			if len(condition.Reason) > 0 {
+				return condition.Reason
+			}
+			return "Pending"
+		case v1.PodResizeInProgress:
+			inProgress = "InProgress"
+			if condition.Reason == v1.PodReasonError {
+				inProgress = v1.PodReasonError
+			}
+		}
+	}
+
+	if len(inProgress) > 0 {
+		return inProgress
+	}
+
+	// older clusters only set the deprecated resize field
+	return string(pod.Status.Resize)
+}
+
+// resourceResizeColour returns the colour of the resize state
+func resourceResizeColour(state string) [2]int {
+	switch state {
+	case "":
+		return [2]int{-1, 0}
+	case v1.PodReasonInfeasible, v1.PodReasonError:
+		return colourBad
+	default:
+		return colourWarn
+	}
+}
+
+// resourceListEqual returns true when both lists hold the same quantities
+func resourceListEqual(a v1.ResourceList, b v1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for name, quantity := range a {
+		value, ok := b[name]
+		if !ok || quantity.Cmp(value) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (s *resource) statsProcessTableRow(res v1.ResourceRequirements, metrics v1.ResourceList, info BuilderInformation, resource string) []Cell {
 	var cellList []Cell
 	var displayValue, request, limit, percentLimit, percentRequest string
@@ -394,7 +771,8 @@ func (s *resource) statsProcessTableRow(res v1.ResourceRequirements, metrics v1.
 	return cellList
 }
 
-func (s *resource) podMetrics2Hashtable(stateList []v1beta1.PodMetrics) map[string]map[string]v1.ResourceList {
+// podMetrics2Hashtable converts the metrics list to a map of pod name and container name
+func podMetrics2Hashtable(stateList []v1beta1.PodMetrics) map[string]map[string]v1.ResourceList {
 	podState := make(map[string]map[string]v1.ResourceList)
 
 	for _, pod := range stateList {
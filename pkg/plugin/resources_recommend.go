@@ -0,0 +1,339 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+	v1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// resourceSampleMetrics reads the pod metrics count times waiting interval between each read, the
+// first read is passed in as it has already been made
+func resourceSampleMetrics(connect *Connector, args []string, first []v1beta1.PodMetrics, count int, interval time.Duration) ([][]v1beta1.PodMetrics, error) {
+	log := logger{location: "resources:resourceSampleMetrics"}
+	log.Debug("Start")
+
+	samples := [][]v1beta1.PodMetrics{first}
+	for len(samples) < count {
+		time.Sleep(interval)
+		log.Debug("reading sample", len(samples)+1, "of", count)
+
+		podStateList, err := connect.GetMetricPods(args)
+		if err != nil {
+			return samples, err
+		}
+		samples = append(samples, podStateList)
+	}
+
+	return samples, nil
+}
+
+// resourceReadMetricsFile reads pod metrics exported from another source, the file holds one or
+// more PodMetricsList or PodMetrics documents separated by --- and each document is treated as a
+// separate sample
+func resourceReadMetricsFile(filename string) ([][]v1beta1.PodMetrics, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics file: %w", err)
+	}
+
+	samples := [][]v1beta1.PodMetrics{}
+	for _, document := range strings.Split(string(content), "\n---") {
+		if len(strings.TrimSpace(document)) == 0 {
+			continue
+		}
+
+		var metricsList v1beta1.PodMetricsList
+		if err := yaml.Unmarshal([]byte(document), &metricsList); err != nil {
+			return nil, fmt.Errorf("failed to read metrics file: %w", err)
+		}
+
+		if metricsList.Kind == "PodMetrics" {
+			var metrics v1beta1.PodMetrics
+			if err := yaml.Unmarshal([]byte(document), &metrics); err != nil {
+				return nil, fmt.Errorf("failed to read metrics file: %w", err)
+			}
+			samples = append(samples, []v1beta1.PodMetrics{metrics})
+			continue
+		}
+
+		samples = append(samples, metricsList.Items)
+	}
+
+	return samples, nil
+}
+
+// resourceUsageSamples groups the usage of every sample by namespace/pod and container name
+func resourceUsageSamples(samples [][]v1beta1.PodMetrics) map[string]map[string][]v1.ResourceList {
+	usage := make(map[string]map[string][]v1.ResourceList)
+	for _, sample := range samples {
+		for _, pod := range sample {
+			id := pod.Namespace + "/" + pod.Name
+			if _, ok := usage[id]; !ok {
+				usage[id] = make(map[string][]v1.ResourceList)
+			}
+			for _, container := range pod.Containers {
+				usage[id][container.Name] = append(usage[id][container.Name], container.Usage)
+			}
+		}
+	}
+	return usage
+}
+
+// resourceRecommend returns the recommended request and limit from the usage samples, the request
+// is the average usage plus requestHeadroom percent and the limit is the peak usage plus
+// limitHeadroom percent. cpu is rounded up to the nearest millicore and memory to the nearest Mi,
+// false is returned when there are no samples
+func resourceRecommend(samples []v1.ResourceList, resourceType string, requestHeadroom int64, limitHeadroom int64) (apires.Quantity, apires.Quantity, bool) {
+	name := v1.ResourceName(resourceType)
+
+	var total, peak float64
+	var count int
+	for _, sample := range samples {
+		quantity, ok := sample[name]
+		if !ok {
+			continue
+		}
+
+		value := float64(quantity.Value())
+		if resourceType == "cpu" {
+			value = float64(quantity.MilliValue())
+		}
+
+		total += value
+		peak = math.Max(peak, value)
+		count++
+	}
+
+	if count == 0 {
+		return apires.Quantity{}, apires.Quantity{}, false
+	}
+
+	request := total / float64(count) * float64(100+requestHeadroom) / 100
+	limit := math.Max(peak*float64(100+limitHeadroom)/100, request)
+
+	if resourceType == "cpu" {
+		return resourceRoundUp(request, 1, apires.DecimalSI), resourceRoundUp(limit, 1, apires.DecimalSI), true
+	}
+
+	return resourceRoundUp(request, 1024*1024, apires.BinarySI), resourceRoundUp(limit, 1024*1024, apires.BinarySI), true
+}
+
+// resourceRoundUp rounds value up to a multiple of step, cpu values are millicores so are returned
+// as a milli quantity
+func resourceRoundUp(value float64, step int64, format apires.Format) apires.Quantity {
+	rounded := int64(math.Max(math.Ceil(value/float64(step)), 1)) * step
+	if format == apires.DecimalSI {
+		return *apires.NewMilliQuantity(rounded, format)
+	}
+	return *apires.NewQuantity(rounded, format)
+}
+
+// recommendCells returns the RECOMMENDED-REQUEST, RECOMMENDED-LIMIT and SAVINGS columns, savings
+// are the current request minus the recommended request so a negative value means the container
+// needs more than it requests
+func (s *resource) recommendCells(spec v1.ResourceRequirements, info BuilderInformation) []Cell {
+	request, limit, ok := resourceRecommend(s.Samples[info.Namespace+"/"+info.PodName][info.Name], s.ResourceType, s.RequestHeadroom, s.LimitHeadroom)
+	if !ok {
+		return []Cell{NewCellText(""), NewCellText(""), NewCellText("")}
+	}
+
+	// each value is compared to itself so the cells are not coloured as a mismatch
+	name := v1.ResourceName(s.ResourceType)
+	requestCell := s.resourceCell(v1.ResourceList{name: request}, v1.ResourceList{name: request})
+	limitCell := s.resourceCell(v1.ResourceList{name: limit}, v1.ResourceList{name: limit})
+
+	savings := s.resourceValue(spec.Requests) - requestCell.number
+	return []Cell{requestCell, limitCell, s.savingsCell(savings)}
+}
+
+// savingsCell formats the savings in the same units as the REQUEST column
+func (s *resource) savingsCell(savings int64) Cell {
+	colour := colourOk
+	if savings < 0 {
+		colour = colourWarn
+	}
+
+	text := memoryHumanReadable(savings, s.BytesAs)
+	if s.ResourceType == "cpu" {
+		if s.ShowRaw {
+			text = fmt.Sprintf("%dn", savings)
+		} else {
+			text = fmt.Sprintf("%dm", savings)
+		}
+	}
+
+	return NewCellColourInt(colour, text, savings)
+}
+
+// resourcePatch holds the recommended resources of each container in a workload
+type resourcePatch struct {
+	kind           string
+	name           string
+	namespace      string
+	template       v1.PodSpec // pod template of the workload, only its containers are patched
+	initContainers []string   // container names in the order they are listed in the template
+	containers     []string
+	resources      map[string]v1.ResourceRequirements
+}
+
+// resourceBuildPatches creates a patch for each Deployment, StatefulSet and DaemonSet in the owner
+// tree, when there are many pods the largest recommendation for each container is used
+func (s *resource) resourceBuildPatches(tree []*LeafNode) []*resourcePatch {
+	patches := []*resourcePatch{}
+	found := make(map[string]*resourcePatch)
+
+	var walk func(nodes []*LeafNode, owner *resourcePatch)
+	walk = func(nodes []*LeafNode, owner *resourcePatch) {
+		for _, node := range nodes {
+			current := owner
+			switch node.kind {
+			case TypeNameDeployment, TypeNameStatefulSet, TypeNameDaemonSet:
+				// the same workload is listed under every node it runs on
+				key := node.kind + "/" + node.namespace + "/" + node.name
+				if _, ok := found[key]; !ok {
+					template, _ := ownerPodTemplate(node.data)
+					found[key] = &resourcePatch{
+						kind:      node.kind,
+						name:      node.name,
+						namespace: node.namespace,
+						template:  template,
+						resources: make(map[string]v1.ResourceRequirements),
+					}
+					patches = append(patches, found[key])
+				}
+				current = found[key]
+			case TypeNamePod:
+				if current != nil {
+					s.addPodToPatch(current, node.data.pod)
+				}
+			}
+			walk(node.child, current)
+		}
+	}
+	walk(tree, nil)
+
+	// only keep the workloads we have a recommendation for
+	out := []*resourcePatch{}
+	for _, patch := range patches {
+		if len(patch.resources) > 0 {
+			out = append(out, patch)
+		}
+	}
+	return out
+}
+
+// addPodToPatch adds the recommendations for the pods containers to the patch, only containers that
+// exist in the owners pod template are added as containers injected into the pod (by a webhook for
+// example) cant be patched, containers skipped using -c are also left out
+func (s *resource) addPodToPatch(patch *resourcePatch, pod v1.Pod) {
+	name := v1.ResourceName(s.ResourceType)
+	id := pod.Namespace + "/" + pod.Name
+
+	add := func(containers []v1.Container, names *[]string) {
+		for _, container := range containers {
+			if skipContainerName(s.Flags, container.Name) {
+				continue
+			}
+
+			request, limit, ok := resourceRecommend(s.Samples[id][container.Name], s.ResourceType, s.RequestHeadroom, s.LimitHeadroom)
+			if !ok {
+				continue
+			}
+
+			current, seen := patch.resources[container.Name]
+			if !seen {
+				*names = append(*names, container.Name)
+				current = v1.ResourceRequirements{Requests: v1.ResourceList{}, Limits: v1.ResourceList{}}
+			}
+			schedulingMaxResources(current.Requests, v1.ResourceList{name: request})
+			schedulingMaxResources(current.Limits, v1.ResourceList{name: limit})
+			patch.resources[container.Name] = current
+		}
+	}
+
+	add(patch.template.InitContainers, &patch.initContainers)
+	add(patch.template.Containers, &patch.containers)
+}
+
+// yaml returns the patch as a strategic merge patch, the apiVersion, kind and metadata are
+// included so the patch can also be used by kustomize
+func (p *resourcePatch) yaml() ([]byte, error) {
+	containerList := func(names []string) []map[string]interface{} {
+		list := []map[string]interface{}{}
+		for _, name := range names {
+			res := p.resources[name]
+			list = append(list, map[string]interface{}{
+				"name": name,
+				"resources": map[string]interface{}{
+					"requests": res.Requests,
+					"limits":   res.Limits,
+				},
+			})
+		}
+		return list
+	}
+
+	podSpec := map[string]interface{}{}
+	if len(p.containers) > 0 {
+		podSpec["containers"] = containerList(p.containers)
+	}
+	if len(p.initContainers) > 0 {
+		podSpec["initContainers"] = containerList(p.initContainers)
+	}
+
+	patch := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       p.kind,
+		"metadata": map[string]interface{}{
+			"name":      p.name,
+			"namespace": p.namespace,
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": podSpec,
+			},
+		},
+	}
+
+	return yaml.Marshal(patch)
+}
+
+// filename returns the name of the patch file, the resource type is included so the cpu and
+// memory patches of the same workload can be written to the same directory
+func (p *resourcePatch) filename(resourceType string) string {
+	return fmt.Sprintf("%s-%s-%s-%s.yaml", p.namespace, strings.ToLower(p.kind), p.name, resourceType)
+}
+
+// resourceWritePatches writes each patch to its own file in dir, the directory is created if it
+// does not exist
+func (s *resource) resourceWritePatches(dir string, patches []*resourcePatch) error {
+	log := logger{location: "resources:resourceWritePatches"}
+	log.Debug("Start")
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create patch directory: %w", err)
+	}
+
+	for _, patch := range patches {
+		content, err := patch.yaml()
+		if err != nil {
+			return err
+		}
+
+		filename := filepath.Join(dir, patch.filename(s.ResourceType))
+		if err := os.WriteFile(filename, content, 0644); err != nil {
+			return fmt.Errorf("failed to write patch file: %w", err)
+		}
+		log.Tell("patch written to", filename)
+	}
+
+	return nil
+}
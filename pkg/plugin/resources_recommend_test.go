@@ -0,0 +1,222 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func recommendTestUsage(values ...string) []v1.ResourceList {
+	samples := []v1.ResourceList{}
+	for _, value := range values {
+		quantity := apires.MustParse(value)
+		if strings.HasSuffix(value, "m") {
+			samples = append(samples, v1.ResourceList{v1.ResourceCPU: quantity})
+		} else {
+			samples = append(samples, v1.ResourceList{v1.ResourceMemory: quantity})
+		}
+	}
+	return samples
+}
+
+// *****************
+// resourceRecommend
+// *****************
+
+func TestResourceRecommend(t *testing.T) {
+	tests := []struct {
+		name         string
+		samples      []v1.ResourceList
+		resourceType string
+		request      string
+		limit        string
+		ok           bool
+	}{
+		{"no samples", nil, "cpu", "", "", false},
+		{"single cpu sample", recommendTestUsage("100m"), "cpu", "115m", "150m", true},
+		{"cpu window", recommendTestUsage("100m", "300m"), "cpu", "230m", "450m", true},
+		{"memory rounded up to Mi", recommendTestUsage("40Mi", "60Mi"), "memory", "58Mi", "90Mi", true},
+		{"minimum of 1m", recommendTestUsage("0m"), "cpu", "1m", "1m", true},
+		{"other resource only", recommendTestUsage("64Mi"), "cpu", "", "", false},
+	}
+
+	for _, test := range tests {
+		request, limit, ok := resourceRecommend(test.samples, test.resourceType, 15, 50)
+		if ok != test.ok {
+			t.Errorf("%s: output ok %t not equal to expected %t", test.name, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if request.String() != test.request || limit.String() != test.limit {
+			t.Errorf("%s: output %s %s not equal to expected %s %s", test.name, request.String(), limit.String(), test.request, test.limit)
+		}
+	}
+}
+
+// *****************
+// resourceReadMetricsFile
+// *****************
+
+func TestResourceReadMetricsFile(t *testing.T) {
+	content := `apiVersion: metrics.k8s.io/v1beta1
+kind: PodMetricsList
+items:
+- metadata: {name: web, namespace: default}
+  containers:
+  - {name: app, usage: {cpu: 100m}}
+---
+apiVersion: metrics.k8s.io/v1beta1
+kind: PodMetrics
+metadata: {name: web, namespace: default}
+containers:
+- {name: app, usage: {cpu: 300m}}
+`
+	filename := filepath.Join(t.TempDir(), "metrics.yaml")
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := resourceReadMetricsFile(filename)
+	if err != nil {
+		t.Fatalf("Output error %s not equal to expected nil", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("Output %d samples not equal to expected 2", len(samples))
+	}
+
+	usage := resourceUsageSamples(samples)["default/web"]["app"]
+	if len(usage) != 2 || usage[1].Cpu().MilliValue() != 300 {
+		t.Errorf("Output usage %v not equal to expected 100m and 300m", usage)
+	}
+}
+
+// *****************
+// resourceBuildPatches
+// *****************
+
+func TestResourceBuildPatches(t *testing.T) {
+	s := resource{
+		ResourceType:    "cpu",
+		RequestHeadroom: 0,
+		LimitHeadroom:   100,
+		Samples: map[string]map[string][]v1.ResourceList{
+			"default/web-1": {"app": recommendTestUsage("100m"), "injected": recommendTestUsage("50m")},
+			"default/web-2": {"app": recommendTestUsage("200m"), "proxy": recommendTestUsage("10m")},
+			"default/solo":  {"app": recommendTestUsage("500m")},
+			"other/web-1":   {"app": recommendTestUsage("900m")},
+		},
+	}
+
+	always := v1.ContainerRestartPolicyAlways
+	template := v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "proxy", RestartPolicy: &always}},
+		Containers:     []v1.Container{{Name: "app"}},
+	}
+	// the injected container was added to the pod by a webhook so is not part of the template
+	spec := *template.DeepCopy()
+	spec.Containers = append(spec.Containers, v1.Container{Name: "injected"})
+
+	podNode := func(name string) *LeafNode {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Spec: spec}
+		return &LeafNode{name: name, kind: TypeNamePod, data: ParentData{pod: pod}}
+	}
+	deployment := func(pod *LeafNode) *LeafNode {
+		owner := ParentData{kind: TypeNameDeployment}
+		owner.deployment.Spec.Template.Spec = template
+		return &LeafNode{name: "web", kind: TypeNameDeployment, namespace: "default", data: owner, child: []*LeafNode{
+			{name: "web-abc", kind: TypeNameReplicaSet, namespace: "default", child: []*LeafNode{pod}},
+		}}
+	}
+
+	// the deployment runs on two nodes so is listed twice
+	tree := []*LeafNode{
+		{name: "node-1", kind: TypeNameNode, child: []*LeafNode{deployment(podNode("web-1")), podNode("solo")}},
+		{name: "node-2", kind: TypeNameNode, child: []*LeafNode{deployment(podNode("web-2"))}},
+	}
+
+	patches := s.resourceBuildPatches(tree)
+	if len(patches) != 1 {
+		t.Fatalf("Output %d patches not equal to expected 1", len(patches))
+	}
+
+	patch := patches[0]
+	if patch.filename("cpu") != "default-deployment-web-cpu.yaml" {
+		t.Errorf("Output filename %s not equal to expected default-deployment-web-cpu.yaml", patch.filename("cpu"))
+	}
+
+	// the largest recommendation of both pods is used
+	app := patch.resources["app"]
+	if app.Requests.Cpu().String() != "200m" || app.Limits.Cpu().String() != "400m" {
+		t.Errorf("Output app %s %s not equal to expected 200m 400m", app.Requests.Cpu().String(), app.Limits.Cpu().String())
+	}
+	if _, ok := patch.resources["injected"]; ok {
+		t.Errorf("Output patch includes the injected container which is not in the template")
+	}
+
+	content, err := patch.yaml()
+	if err != nil {
+		t.Fatalf("Output error %s not equal to expected nil", err)
+	}
+
+	var output struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Spec struct {
+			Template struct {
+				Spec v1.PodSpec `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := yaml.Unmarshal(content, &output); err != nil {
+		t.Fatalf("Output error %s not equal to expected nil", err)
+	}
+
+	if output.Kind != TypeNameDeployment || output.Metadata.Name != "web" || output.Metadata.Namespace != "default" {
+		t.Errorf("Output %s %s/%s not equal to expected Deployment default/web", output.Kind, output.Metadata.Namespace, output.Metadata.Name)
+	}
+	podSpec := output.Spec.Template.Spec
+	if len(podSpec.Containers) != 1 || podSpec.Containers[0].Resources.Requests.Cpu().String() != "200m" {
+		t.Errorf("Output containers %v not equal to expected app with a request of 200m", podSpec.Containers)
+	}
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != "proxy" {
+		t.Errorf("Output init containers %v not equal to expected proxy", podSpec.InitContainers)
+	}
+}
+
+func TestResourceBuildPatchesContainerFilter(t *testing.T) {
+	s := resource{
+		ResourceType: "cpu",
+		Flags:        commonFlags{container: "app"},
+		Samples: map[string]map[string][]v1.ResourceList{
+			"default/web-1": {"app": recommendTestUsage("100m"), "log": recommendTestUsage("10m")},
+		},
+	}
+
+	owner := ParentData{kind: TypeNameStatefulSet}
+	owner.stateful.Spec.Template.Spec = v1.PodSpec{Containers: []v1.Container{{Name: "app"}, {Name: "log"}}}
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}, Spec: owner.stateful.Spec.Template.Spec}
+	tree := []*LeafNode{{name: "node-1", kind: TypeNameNode, child: []*LeafNode{
+		{name: "web", kind: TypeNameStatefulSet, namespace: "default", data: owner, child: []*LeafNode{
+			{name: "web-1", kind: TypeNamePod, data: ParentData{pod: pod}},
+		}},
+	}}}
+
+	patches := s.resourceBuildPatches(tree)
+	if len(patches) != 1 {
+		t.Fatalf("Output %d patches not equal to expected 1", len(patches))
+	}
+	if len(patches[0].containers) != 1 || patches[0].containers[0] != "app" {
+		t.Errorf("Output containers %v not equal to expected [app]", patches[0].containers)
+	}
+}
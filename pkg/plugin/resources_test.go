@@ -0,0 +1,157 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+)
+
+func resourcesTestRequirements(request string, limit string) v1.ResourceRequirements {
+	return v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: apires.MustParse(request)},
+		Limits:   v1.ResourceList{v1.ResourceCPU: apires.MustParse(limit)},
+	}
+}
+
+// *****************
+// resourceResizeStatus
+// *****************
+
+func TestResourceResizeStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []v1.PodCondition
+		resize     v1.PodResizeStatus
+		expected   string
+	}{
+		{"no resize", nil, "", ""},
+		{"pending", []v1.PodCondition{{Type: v1.PodResizePending, Status: v1.ConditionTrue, Reason: v1.PodReasonDeferred}}, "", "Deferred"},
+		{"in progress", []v1.PodCondition{{Type: v1.PodResizeInProgress, Status: v1.ConditionTrue}}, "", "InProgress"},
+		{"in progress error", []v1.PodCondition{{Type: v1.PodResizeInProgress, Status: v1.ConditionTrue, Reason: v1.PodReasonError}}, "", "Error"},
+		{"new resize replaces in progress", []v1.PodCondition{
+			{Type: v1.PodResizeInProgress, Status: v1.ConditionTrue},
+			{Type: v1.PodResizePending, Status: v1.ConditionTrue, Reason: v1.PodReasonInfeasible},
+		}, "", "Infeasible"},
+		{"deprecated field", nil, v1.PodResizeStatusInProgress, "InProgress"},
+	}
+
+	for _, test := range tests {
+		pod := v1.Pod{Status: v1.PodStatus{Conditions: test.conditions, Resize: test.resize}}
+		output := resourceResizeStatus(pod)
+		if output != test.expected {
+			t.Errorf("%s: output %s not equal to expected %s", test.name, output, test.expected)
+		}
+	}
+}
+
+// *****************
+// BuildBranch
+// *****************
+
+func TestResourceBuildBranchPod(t *testing.T) {
+	s := resource{ResourceType: "cpu", ShowInit: true}
+
+	pod := v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "app", Resources: resourcesTestRequirements("400m", "800m")},
+				{Name: "log", Resources: resourcesTestRequirements("100m", "100m")},
+			},
+			Overhead: v1.ResourceList{v1.ResourceCPU: apires.MustParse("50m")},
+		},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{{Type: v1.PodResizeInProgress, Status: v1.ConditionTrue}},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", Resources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: apires.MustParse("200m")},
+					Limits:   v1.ResourceList{v1.ResourceCPU: apires.MustParse("400m")},
+				}},
+				{Name: "log", AllocatedResources: v1.ResourceList{v1.ResourceCPU: apires.MustParse("100m")}},
+			},
+		},
+	}
+
+	info := BuilderInformation{TypeName: TypeNamePod, Data: ParentData{pod: pod}}
+	rows := [][]Cell{}
+	for _, container := range pod.Spec.Containers {
+		info.ContainerType = TypeIDContainer
+		info.Name = container.Name
+		row, _ := s.BuildContainerSpec(container, info)
+		rows = append(rows, row...)
+	}
+
+	if rows[0][5].text != "200m" || rows[0][6].text != "400m" || rows[0][7].text != "InProgress" {
+		t.Errorf("Output app %s %s %s not equal to expected 200m 400m InProgress", rows[0][5].text, rows[0][6].text, rows[0][7].text)
+	}
+	// the log container matches its spec so is not part of the resize
+	if rows[1][7].text != "" {
+		t.Errorf("Output log resize %s not equal to expected empty", rows[1][7].text)
+	}
+
+	branch, _ := s.BuildBranch(BuilderInformation{TypeName: TypeNamePod, Data: ParentData{pod: pod}}, rows)
+	tests := []struct {
+		name     string
+		cell     Cell
+		expected string
+	}{
+		{"request", branch[1], "550m"},
+		{"limit", branch[2], "950m"},
+		{"actual request", branch[5], "350m"},
+		{"actual limit", branch[6], "550m"},
+		{"resize", branch[7], "InProgress"},
+	}
+	for _, test := range tests {
+		if test.cell.text != test.expected {
+			t.Errorf("%s: output %s not equal to expected %s", test.name, test.cell.text, test.expected)
+		}
+	}
+
+	// pod level resources replace the container totals
+	pod.Spec.Resources = &v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: apires.MustParse("1")}}
+	branch, _ = s.BuildBranch(BuilderInformation{TypeName: TypeNamePod, Data: ParentData{pod: pod}}, rows)
+	if branch[1].text != "1050m" {
+		t.Errorf("Output pod level request %s not equal to expected 1050m", branch[1].text)
+	}
+
+	// when containers are filtered only the listed rows are totalled
+	filtered := resource{ResourceType: "cpu", ShowInit: true, FilterContainer: true}
+	branch, _ = filtered.BuildBranch(BuilderInformation{TypeName: TypeNamePod, Data: ParentData{pod: pod}}, rows[:1])
+	if branch[1].text != "400m" || branch[2].text != "800m" || branch[5].text != "200m" || branch[6].text != "400m" {
+		t.Errorf("Output filtered %s %s %s %s not equal to expected 400m 800m 200m 400m", branch[1].text, branch[2].text, branch[5].text, branch[6].text)
+	}
+
+	owner, _ := s.BuildBranch(BuilderInformation{TypeName: TypeNameReplicaSet}, [][]Cell{branch, branch})
+	if owner[7].text != "InProgress:2" {
+		t.Errorf("Output owner resize %s not equal to expected InProgress:2", owner[7].text)
+	}
+}
+
+// *****************
+// showsAllContainers
+// *****************
+
+func TestResourceShowsAllContainers(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	sidecarPod := v1.Pod{Spec: v1.PodSpec{InitContainers: []v1.Container{{Name: "proxy", RestartPolicy: &always}}}}
+	initPod := v1.Pod{Spec: v1.PodSpec{InitContainers: []v1.Container{{Name: "setup"}}}}
+
+	tests := []struct {
+		name     string
+		s        resource
+		pod      v1.Pod
+		expected bool
+	}{
+		{"no filter", resource{ShowInit: true}, initPod, true},
+		{"container filter", resource{ShowInit: true, FilterContainer: true}, initPod, false},
+		{"init containers hidden", resource{}, initPod, false},
+		{"only sidecars hidden", resource{}, sidecarPod, true},
+	}
+
+	for _, test := range tests {
+		output := test.s.showsAllContainers(test.pod)
+		if output != test.expected {
+			t.Errorf("%s: output %t not equal to expected %t", test.name, output, test.expected)
+		}
+	}
+}
@@ -2,9 +2,12 @@ package plugin
 
 import (
 	"fmt"
+	"regexp"
+	"time"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
+	duration "k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -13,7 +16,13 @@ var restartsShort = "Show restart counts for each container in a named pod"
 var restartsDescription = ` Prints container name and restart count for individual containers. If no name is specified the
 container restart counts of all pods in the current namespace are shown.
 
-The T column in the table output denotes S for Standard, I for init and E for Ephemerial containers`
+Along with the count the reason, exit code and finish time of the last termination are shown. The
+RATE column is the number of restarts per hour since the pod was started and CRASHLOOP is true when
+the container is waiting in CrashLoopBackOff, BACKOFF shows the current back-off delay reported by
+the kubelet. When using --oddities rows are shown if either the restart count or the rate is an
+outlier.
+
+The T column in the table output denotes S for Standard, I for init, K for sidecar and E for Ephemerial containers`
 
 var restartsExample = `  # List individual container restart count from pods
   %[1]s restarts
@@ -40,7 +49,13 @@ var restartsExample = `  # List individual container restart count from pods
   %[1]s restarts -l app=web
 
   # List restart count from all containers where the pod label app is either web or mail
-  %[1]s restarts -l "app in (web,mail)"`
+  %[1]s restarts -l "app in (web,mail)"
+
+  # List only containers that have restarted in the last hour
+  %[1]s restarts --since 1h
+
+  # List containers that are stuck in a crash loop
+  %[1]s restarts --match CRASHLOOP==true`
 
 func Restarts(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
 
@@ -48,6 +63,13 @@ func Restarts(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 	log.Debug("Start")
 
 	loopinfo := restarts{}
+	if since := cmd.Flag("since").Value.String(); len(since) > 0 {
+		sinceDuration, err := time.ParseDuration(since)
+		if err != nil {
+			return fmt.Errorf("invalid value for --since: %w", err)
+		}
+		loopinfo.Since = sinceDuration
+	}
 	builder := RowBuilder{}
 	builder.LoopStatus = true
 	builder.ShowInitContainers = true
@@ -73,7 +95,9 @@ func Restarts(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 	builder.Table = &table
 	builder.ShowTreeView = commonFlagList.showTreeView
 
-	builder.Build(loopinfo)
+	if err := builder.Build(loopinfo); err != nil {
+		return err
+	}
 
 	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
 		return err
@@ -81,10 +105,26 @@ func Restarts(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 
 	// do we need to find the outliers, we have enough data to compute a range
 	if commonFlagList.showOddities {
-		row2Remove, err := table.ListOutOfRange(4) //3 = restarts column
+		countRemove, err := table.ListOutOfRange(builder.DefaultHeaderLen) // restarts column
+		if err != nil {
+			return err
+		}
+		rateRemove, err := table.ListOutOfRange(builder.DefaultHeaderLen + 1) // rate column
 		if err != nil {
 			return err
 		}
+
+		// only remove the rows that are in range for both columns
+		rateRows := make(map[int]bool)
+		for _, row := range rateRemove {
+			rateRows[row] = true
+		}
+		row2Remove := []int{}
+		for _, row := range countRemove {
+			if rateRows[row] {
+				row2Remove = append(row2Remove, row)
+			}
+		}
 		table.HideRows(row2Remove)
 	}
 
@@ -93,39 +133,56 @@ func Restarts(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 
 }
 
-type restarts struct{}
+// matches the back-off delay in the CrashLoopBackOff waiting message
+var restartsBackOffRegexp = regexp.MustCompile(`back-off (\S+)`)
+
+type restarts struct {
+	Since time.Duration // only show containers that restarted within this duration, 0 shows all
+}
 
 func (s restarts) Headers() []string {
 	return []string{
-		"RESTARTS",
+		"RESTARTS", "RATE", "CRASHLOOP", "BACKOFF", "LAST-REASON", "LAST-EXIT-CODE", "LAST-FINISHED",
 	}
 }
 
 func (s restarts) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
-	out := make([][]Cell, 1)
-	out[0] = s.restartsBuildRow(info, container.RestartCount)
-	return out, nil
+	return s.restartsBuildRows(info, container, time.Now()), nil
 }
 
 func (s restarts) BuildEphemeralContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
-	out := make([][]Cell, 1)
-	out[0] = s.restartsBuildRow(info, container.RestartCount)
-	return out, nil
+	return s.restartsBuildRows(info, container, time.Now()), nil
 }
 
 func (s restarts) HideColumns(info BuilderInformation) []int {
 	return []int{}
 }
 
+// BuildBranch adds up the restart counts and rates, the branch is in a crash loop if any of its
+// children are
 func (s restarts) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	rowOut := make([]Cell, 1)
+	rowOut := make([]Cell, len(s.Headers()))
+	for i := range rowOut {
+		rowOut[i] = NewCellText("")
+	}
 
-	switch info.TypeName {
-	case "Pod":
-		for _, r := range rows {
-			rowOut[0].number += r[0].number // ready
+	var count int64
+	var rate float64
+	var crashLoops int64
+	for _, r := range rows {
+		count += r[0].number
+		rate += r[1].float
+		if r[2].text == "true" {
+			crashLoops++
+		} else {
+			crashLoops += r[2].number
 		}
-		rowOut[0].text = fmt.Sprintf("%d", rowOut[0].number)
+	}
+
+	rowOut[0] = NewCellInt(fmt.Sprintf("%d", count), count)
+	rowOut[1] = NewCellFloat(fmt.Sprintf("%.2f", rate), rate)
+	if crashLoops > 0 {
+		rowOut[2] = NewCellColourInt(colourBad, fmt.Sprintf("%d", crashLoops), crashLoops)
 	}
 
 	return rowOut, nil
@@ -141,14 +198,75 @@ func (s restarts) BuildEphemeralContainerSpec(container v1.EphemeralContainer, i
 	return out, nil
 }
 
-func (s restarts) restartsBuildRow(info BuilderInformation, restartCount int32) []Cell {
-	var cellList []Cell
+func (s restarts) restartsBuildRows(info BuilderInformation, container v1.ContainerStatus, now time.Time) [][]Cell {
+	lastTerminated := container.LastTerminationState.Terminated
+
+	if s.Since > 0 {
+		// the previous container finishing is the time of the most recent restart
+		if lastTerminated == nil || now.Sub(lastTerminated.FinishedAt.Time) > s.Since {
+			return [][]Cell{}
+		}
+	}
+
+	rate := restartsRate(container.RestartCount, restartsStartTime(info.Data.pod), now)
+	crashLoop, backOff := restartsCrashLoop(container.State)
+
+	lastReason := NewCellText("")
+	lastExitCode := NewCellText("")
+	lastFinished := NewCellText("")
+	if lastTerminated != nil {
+		lastReason = NewCellText(lastTerminated.Reason)
+		lastExitCode = NewCellColourInt(setColourBoolean(lastTerminated.ExitCode == 0), fmt.Sprintf("%d", lastTerminated.ExitCode), int64(lastTerminated.ExitCode))
+		lastFinished = NewCellText(lastTerminated.FinishedAt.Format(timestampFormat) + " (" + duration.HumanDuration(now.Sub(lastTerminated.FinishedAt.Time)) + ")")
+	}
+
+	return [][]Cell{{
+		NewCellInt(fmt.Sprintf("%d", container.RestartCount), int64(container.RestartCount)),
+		NewCellFloat(fmt.Sprintf("%.2f", rate), rate),
+		NewCellColourText(setColourBoolean(!crashLoop), fmt.Sprintf("%t", crashLoop)),
+		NewCellText(backOff),
+		lastReason,
+		lastExitCode,
+		lastFinished,
+	}}
+}
+
+// restartsStartTime returns the time the pod was started falling back to the creation time, the
+// container status only holds the start time of the current container so the pod start time is
+// used as the time the container first started
+func restartsStartTime(pod v1.Pod) time.Time {
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// restartsRate returns the number of restarts per hour between started and now
+func restartsRate(restartCount int32, started time.Time, now time.Time) float64 {
+	if started.IsZero() || restartCount == 0 {
+		return 0
+	}
+
+	hours := now.Sub(started).Hours()
+	if hours <= 0 {
+		return 0
+	}
 
-	cellList = append(cellList,
-		NewCellInt(fmt.Sprintf("%d", restartCount), int64(restartCount)),
-	)
+	return float64(restartCount) / hours
+}
 
-	return cellList
+// restartsCrashLoop returns true when the container is waiting in CrashLoopBackOff along with the
+// current back-off delay taken from the waiting message
+func restartsCrashLoop(state v1.ContainerState) (bool, string) {
+	if state.Waiting == nil || state.Waiting.Reason != "CrashLoopBackOff" {
+		return false, ""
+	}
+
+	match := restartsBackOffRegexp.FindStringSubmatch(state.Waiting.Message)
+	if match == nil {
+		return true, ""
+	}
+	return true, match[1]
 }
 
 func (s restarts) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
@@ -0,0 +1,143 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// *****************
+// restartsRate
+// *****************
+
+func TestRestartsRate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		count    int32
+		started  time.Time
+		expected float64
+	}{
+		{6, now.Add(-2 * time.Hour), 3},
+		{1, now.Add(-30 * time.Minute), 2},
+		{0, now.Add(-2 * time.Hour), 0},
+		{5, time.Time{}, 0},
+		{5, now.Add(time.Hour), 0},
+	}
+
+	for _, test := range tests {
+		output := restartsRate(test.count, test.started, now)
+		if output != test.expected {
+			t.Errorf("Output %f not equal to expected %f, for count %d", output, test.expected, test.count)
+		}
+	}
+}
+
+// *****************
+// restartsCrashLoop
+// *****************
+
+func TestRestartsCrashLoop(t *testing.T) {
+	tests := []struct {
+		state           v1.ContainerState
+		expectedLoop    bool
+		expectedBackOff string
+	}{
+		{v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 2m40s restarting failed container=app pod=app-1_default(1234)"}}, true, "2m40s"},
+		{v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}, true, ""},
+		{v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}}, false, ""},
+		{v1.ContainerState{Running: &v1.ContainerStateRunning{}}, false, ""},
+	}
+
+	for _, test := range tests {
+		loop, backOff := restartsCrashLoop(test.state)
+		if loop != test.expectedLoop || backOff != test.expectedBackOff {
+			t.Errorf("Output %t %s not equal to expected %t %s", loop, backOff, test.expectedLoop, test.expectedBackOff)
+		}
+	}
+}
+
+// *****************
+// restartsBuildRows
+// *****************
+
+func TestRestartsBuildRowsSince(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := restarts{Since: time.Hour}
+
+	container := func(finished time.Time) v1.ContainerStatus {
+		return v1.ContainerStatus{
+			RestartCount: 3,
+			LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+				Reason:     "OOMKilled",
+				ExitCode:   137,
+				FinishedAt: metav1.NewTime(finished),
+			}},
+		}
+	}
+
+	if rows := s.restartsBuildRows(BuilderInformation{}, container(now.Add(-30*time.Minute)), now); len(rows) != 1 {
+		t.Errorf("Expected the recently restarted container to be listed")
+	}
+	if rows := s.restartsBuildRows(BuilderInformation{}, container(now.Add(-2*time.Hour)), now); len(rows) != 0 {
+		t.Errorf("Expected the container restarted 2h ago to be skipped")
+	}
+	if rows := s.restartsBuildRows(BuilderInformation{}, v1.ContainerStatus{}, now); len(rows) != 0 {
+		t.Errorf("Expected the container that never restarted to be skipped")
+	}
+}
+
+func TestRestartsOdditiesSinceEmpty(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := restarts{Since: time.Hour}
+
+	// the only restart was 2h ago so --since 1h leaves the table empty
+	container := v1.ContainerStatus{
+		RestartCount: 1,
+		LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+			Reason:     "Error",
+			FinishedAt: metav1.NewTime(now.Add(-2 * time.Hour)),
+		}},
+	}
+
+	tbl := Table{}
+	tbl.SetHeader(s.Headers()...)
+	for _, row := range s.restartsBuildRows(BuilderInformation{}, container, now) {
+		tbl.AddRow(row...)
+	}
+
+	// --oddities checks both the restarts and rate columns
+	for _, column := range []int{0, 1} {
+		output, err := tbl.ListOutOfRange(column)
+		if err != nil || len(output) != 0 {
+			t.Errorf("Output %v %v not equal to expected empty list and nil error", output, err)
+		}
+	}
+}
+
+// *****************
+// BuildBranch
+// *****************
+
+func TestRestartsBuildBranch(t *testing.T) {
+	s := restarts{}
+	now := time.Now()
+
+	info := BuilderInformation{TypeName: TypeNamePod}
+	info.Data.pod.Status.StartTime = &metav1.Time{Time: now.Add(-4 * time.Hour)}
+
+	rows := s.restartsBuildRows(info, v1.ContainerStatus{RestartCount: 4, State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}, now)
+	rows = append(rows, s.restartsBuildRows(info, v1.ContainerStatus{RestartCount: 8}, now)...)
+
+	pod, _ := s.BuildBranch(info, rows)
+	if pod[0].number != 12 || pod[1].text != "3.00" || pod[2].number != 1 {
+		t.Errorf("Output pod branch %s %s %s not equal to expected 12 3.00 1", pod[0].text, pod[1].text, pod[2].text)
+	}
+
+	owner, _ := s.BuildBranch(BuilderInformation{TypeName: "ReplicaSet"}, [][]Cell{pod, pod})
+	if owner[0].number != 24 || owner[1].text != "6.00" || owner[2].number != 2 {
+		t.Errorf("Output owner branch %s %s %s not equal to expected 24 6.00 2", owner[0].text, owner[1].text, owner[2].text)
+	}
+}
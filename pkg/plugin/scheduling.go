@@ -0,0 +1,499 @@
+package plugin
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var schedulingShort = "Show why pods are waiting to be scheduled"
+
+var schedulingDescription = ` Prints the PodScheduled condition of each pod along with the reason and message given by the
+scheduler. For pods that have not been scheduled every node in the cluster is checked against the
+pods nodeSelector, required node affinity, tolerations and resource requests, each node is listed
+under the pod showing which of the checks failed.
+
+Resource requests are compared to the nodes allocatable resources minus the requests of the pods
+already running on the node. Node checks are only run against a live cluster, when reading pods
+from a file only the scheduler condition is shown.`
+
+var schedulingExample = `  # List the scheduling state of all pods in the current namespace
+  %[1]s scheduling
+
+  # Show which nodes a pending pod can not be scheduled to
+  %[1]s scheduling my-pod-4jh36
+
+  # List only the nodes that would accept the pod
+  %[1]s scheduling my-pod-4jh36 --match 'FITS==true'
+
+  # Show all pending pods grouped under an <unscheduled> node
+  %[1]s scheduling --node-tree`
+
+func Scheduling(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Scheduling"}
+	log.Debug("Start")
+
+	loopinfo := scheduling{}
+	builder := RowBuilder{}
+	builder.DontListContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	// nodes can only be checked when we are connected to a cluster
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+	if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
+		loopinfo.Connection = &connect
+	}
+
+	table := Table{}
+	table.ColourOutput = commonFlagList.outputAsColour
+	table.CustomColours = commonFlagList.useTheseColours
+
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type scheduling struct {
+	Connection *Connector // nil when the pods are read from a file
+}
+
+// schedulingFit holds the result of each check for a single node, an empty string means the
+// check passed
+type schedulingFit struct {
+	selector  string
+	affinity  string
+	taints    string
+	resources string
+}
+
+func (f schedulingFit) fits() bool {
+	return len(f.selector) == 0 && len(f.affinity) == 0 && len(f.taints) == 0 && len(f.resources) == 0
+}
+
+func (s *scheduling) Headers() []string {
+	return []string{
+		"SCHEDULED", "REASON", "MESSAGE", "CANDIDATE", "SELECTOR", "AFFINITY", "TAINTS", "RESOURCES", "FITS",
+	}
+}
+
+func (s *scheduling) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *scheduling) BuildEphemeralContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *scheduling) HideColumns(info BuilderInformation) []int {
+	if s.Connection == nil {
+		// no node checks when reading from a file
+		return []int{3, 4, 5, 6, 7, 8}
+	}
+	return []int{}
+}
+
+// BuildBranch counts the number of pods waiting to be scheduled
+func (s *scheduling) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, len(s.Headers()))
+	for i := range rowOut {
+		rowOut[i] = NewCellText("")
+	}
+
+	if info.TypeName == TypeNamePod {
+		rowOut[0], rowOut[1], rowOut[2] = schedulingConditionCells(info.Data.pod)
+		return rowOut, nil
+	}
+
+	var pending int64
+	for _, r := range rows {
+		if r[0].text != string(v1.ConditionTrue) {
+			pending++
+		}
+	}
+	if pending > 0 {
+		rowOut[0] = NewCellColourText(colourWarn, fmt.Sprintf("%d pending", pending))
+	}
+
+	return rowOut, nil
+}
+
+func (s *scheduling) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *scheduling) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *scheduling) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
+	scheduled, reason, message := schedulingConditionCells(pod)
+
+	out := [][]Cell{{
+		scheduled,
+		reason,
+		message,
+		NewCellText(pod.Spec.NodeName),
+		NewCellText(""),
+		NewCellText(""),
+		NewCellText(""),
+		NewCellText(""),
+		NewCellText(""),
+	}}
+
+	// only pending pods that are waiting for the scheduler need there nodes checked
+	if s.Connection == nil || len(pod.Spec.NodeName) > 0 || pod.DeletionTimestamp != nil {
+		return out, nil
+	}
+
+	for _, node := range s.Connection.GetNodeList() {
+		fit := schedulingCheckNode(pod, node, s.Connection.GetNodePods(node.Name))
+
+		out = append(out, []Cell{
+			NewCellText(""),
+			NewCellText(""),
+			NewCellText(""),
+			NewCellText(node.Name),
+			schedulingCheckCell(fit.selector),
+			schedulingCheckCell(fit.affinity),
+			schedulingCheckCell(fit.taints),
+			schedulingCheckCell(fit.resources),
+			NewCellColourText(setColourBoolean(fit.fits()), fmt.Sprintf("%t", fit.fits())),
+		})
+	}
+
+	return out, nil
+}
+
+// schedulingConditionCells returns the status, reason and message of the PodScheduled condition
+func schedulingConditionCells(pod v1.Pod) (Cell, Cell, Cell) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != v1.PodScheduled {
+			continue
+		}
+		return NewCellColourText(setColourBoolean(condition.Status == v1.ConditionTrue), string(condition.Status)),
+			NewCellText(condition.Reason),
+			NewCellText(condition.Message)
+	}
+
+	if len(pod.Spec.NodeName) > 0 {
+		// the node name was set directly in the spec, the scheduler was bypassed
+		return NewCellColourText(colourOk, string(v1.ConditionTrue)), NewCellText(""), NewCellText("")
+	}
+
+	return NewCellColourText(colourWarn, string(v1.ConditionUnknown)), NewCellText(""), NewCellText("")
+}
+
+func schedulingCheckCell(failure string) Cell {
+	if len(failure) == 0 {
+		return NewCellColourText(colourOk, "ok")
+	}
+	return NewCellColourText(colourBad, failure)
+}
+
+// schedulingCheckNode runs each scheduling check of the pod against the node, nodePods is the list
+// of pods already running on the node
+func schedulingCheckNode(pod v1.Pod, node v1.Node, nodePods []v1.Pod) schedulingFit {
+	return schedulingFit{
+		selector:  schedulingCheckNodeSelector(pod, node),
+		affinity:  schedulingCheckNodeAffinity(pod, node),
+		taints:    schedulingCheckTaints(pod, node),
+		resources: schedulingCheckResources(pod, node, nodePods),
+	}
+}
+
+// schedulingCheckNodeSelector returns the nodeSelector labels that dont match the node
+func schedulingCheckNodeSelector(pod v1.Pod, node v1.Node) string {
+	failed := []string{}
+	for key, value := range pod.Spec.NodeSelector {
+		if node.Labels[key] != value {
+			failed = append(failed, key+"="+value)
+		}
+	}
+	sort.Strings(failed)
+	return strings.Join(failed, ",")
+}
+
+// schedulingCheckNodeAffinity checks the required node affinity terms, the terms are ORed together
+// so the node only fails if no term matches, the first failing requirement of each term is returned
+func schedulingCheckNodeAffinity(pod v1.Pod, node v1.Node) string {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return ""
+	}
+
+	required := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		return ""
+	}
+
+	failed := []string{}
+	for _, term := range required.NodeSelectorTerms {
+		failure := schedulingMatchNodeSelectorTerm(term, node)
+		if len(failure) == 0 {
+			return ""
+		}
+		failed = append(failed, failure)
+	}
+
+	return strings.Join(failed, "|")
+}
+
+// schedulingMatchNodeSelectorTerm returns the first requirement in the term that the node fails,
+// an empty term never matches
+func schedulingMatchNodeSelectorTerm(term v1.NodeSelectorTerm, node v1.Node) string {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return "empty term"
+	}
+
+	for _, req := range term.MatchExpressions {
+		value, found := node.Labels[req.Key]
+		if !schedulingMatchRequirement(req, value, found) {
+			return schedulingRequirementString(req)
+		}
+	}
+
+	// metadata.name is the only supported field
+	for _, req := range term.MatchFields {
+		if req.Key != "metadata.name" || !schedulingMatchRequirement(req, node.Name, true) {
+			return schedulingRequirementString(req)
+		}
+	}
+
+	return ""
+}
+
+// schedulingMatchRequirement checks a single node selector requirement against a label value
+func schedulingMatchRequirement(req v1.NodeSelectorRequirement, value string, found bool) bool {
+	switch req.Operator {
+	case v1.NodeSelectorOpIn:
+		return found && slices.Contains(req.Values, value)
+	case v1.NodeSelectorOpNotIn:
+		return !found || !slices.Contains(req.Values, value)
+	case v1.NodeSelectorOpExists:
+		return found
+	case v1.NodeSelectorOpDoesNotExist:
+		return !found
+	case v1.NodeSelectorOpGt, v1.NodeSelectorOpLt:
+		if !found || len(req.Values) != 1 {
+			return false
+		}
+		nodeValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		reqValue, err := strconv.ParseInt(req.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if req.Operator == v1.NodeSelectorOpGt {
+			return nodeValue > reqValue
+		}
+		return nodeValue < reqValue
+	}
+
+	return false
+}
+
+func schedulingRequirementString(req v1.NodeSelectorRequirement) string {
+	switch req.Operator {
+	case v1.NodeSelectorOpExists, v1.NodeSelectorOpDoesNotExist:
+		return req.Key + " " + string(req.Operator)
+	}
+	return req.Key + " " + string(req.Operator) + " (" + strings.Join(req.Values, ",") + ")"
+}
+
+// schedulingCheckTaints returns the NoSchedule and NoExecute taints on the node that are not
+// tolerated by the pod, a cordoned node is treated as having the unschedulable taint
+func schedulingCheckTaints(pod v1.Pod, node v1.Node) string {
+	taints := node.Spec.Taints
+
+	if node.Spec.Unschedulable {
+		found := false
+		for _, taint := range taints {
+			if taint.Key == v1.TaintNodeUnschedulable {
+				found = true
+			}
+		}
+		if !found {
+			taints = append(taints, v1.Taint{Key: v1.TaintNodeUnschedulable, Effect: v1.TaintEffectNoSchedule})
+		}
+	}
+
+	failed := []string{}
+	for _, taint := range taints {
+		if taint.Effect == v1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		tolerated := false
+		for _, toleration := range pod.Spec.Tolerations {
+			if toleration.ToleratesTaint(&taint) {
+				tolerated = true
+				break
+			}
+		}
+
+		if !tolerated {
+			failed = append(failed, taint.ToString())
+		}
+	}
+
+	return strings.Join(failed, ",")
+}
+
+// schedulingCheckResources compares the pods requests with the nodes allocatable resources minus
+// the requests of the pods already on the node, each resource that does not fit is returned as
+// name:requested>free
+func schedulingCheckResources(pod v1.Pod, node v1.Node, nodePods []v1.Pod) string {
+	requests := schedulingPodRequests(pod)
+
+	used := v1.ResourceList{}
+	podCount := int64(1) // include the pod being scheduled
+	for _, nodePod := range nodePods {
+		if nodePod.UID == pod.UID && nodePod.Name == pod.Name {
+			continue
+		}
+		podCount++
+		for name, quantity := range schedulingPodRequests(nodePod) {
+			total := used[name]
+			total.Add(quantity)
+			used[name] = total
+		}
+	}
+
+	failed := []string{}
+
+	if allocatable, ok := node.Status.Allocatable[v1.ResourcePods]; ok && podCount > allocatable.Value() {
+		failed = append(failed, fmt.Sprintf("pods:%d>%d", podCount, allocatable.Value()))
+	}
+
+	names := []string{}
+	for name := range requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		request := requests[v1.ResourceName(name)]
+		if request.IsZero() {
+			continue
+		}
+
+		free := node.Status.Allocatable[v1.ResourceName(name)].DeepCopy()
+		free.Sub(used[v1.ResourceName(name)])
+
+		if request.Cmp(free) > 0 {
+			if free.Sign() < 0 {
+				free = apires.Quantity{}
+			}
+			failed = append(failed, fmt.Sprintf("%s:%s>%s", name, request.String(), free.String()))
+		}
+	}
+
+	return strings.Join(failed, ",")
+}
+
+// schedulingPodRequests calculates the resources requested by the pod in the same way as the
+// scheduler, init containers run one at a time so only the largest is counted while sidecars keep
+// running and are added to everything started after them. Pod level requests replace the
+// container totals and the pod overhead is always added
+func schedulingPodRequests(pod v1.Pod) v1.ResourceList {
+	return schedulingPodResources(pod, resourceRequests)
+}
+
+// schedulingPodResources totals the resources returned by resources for the whole pod, pod level
+// resources replace the container totals and the pod overhead is added on top
+func schedulingPodResources(pod v1.Pod, resources func(v1.ResourceRequirements) v1.ResourceList) v1.ResourceList {
+	total := schedulingContainerResources(pod, resources)
+
+	if pod.Spec.Resources != nil {
+		for name, quantity := range resources(*pod.Spec.Resources) {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+
+	schedulingAddResources(total, pod.Spec.Overhead)
+
+	return total
+}
+
+// schedulingContainerResources totals the resources returned by resources for each container in
+// the pod, sidecar containers run alongside the containers so are added to them while each init
+// container only needs room for itself and the sidecars started before it
+func schedulingContainerResources(pod v1.Pod, resources func(v1.ResourceRequirements) v1.ResourceList) v1.ResourceList {
+	total := v1.ResourceList{}
+
+	for _, container := range pod.Spec.Containers {
+		schedulingAddResources(total, resources(container.Resources))
+	}
+
+	sidecars := v1.ResourceList{}
+	initMax := v1.ResourceList{}
+	for _, container := range pod.Spec.InitContainers {
+		current := v1.ResourceList{}
+		if isSidecarContainer(container) {
+			schedulingAddResources(sidecars, resources(container.Resources))
+		} else {
+			schedulingAddResources(current, resources(container.Resources))
+		}
+		schedulingAddResources(current, sidecars)
+		schedulingMaxResources(initMax, current)
+	}
+
+	schedulingAddResources(total, sidecars)
+	schedulingMaxResources(total, initMax)
+
+	return total
+}
+
+// schedulingAddResources adds each resource in list to total
+func schedulingAddResources(total v1.ResourceList, list v1.ResourceList) {
+	for name, quantity := range list {
+		value := total[name].DeepCopy()
+		value.Add(quantity)
+		total[name] = value
+	}
+}
+
+// schedulingMaxResources sets each resource in total to the larger of the two values
+func schedulingMaxResources(total v1.ResourceList, list v1.ResourceList) {
+	for name, quantity := range list {
+		if value, ok := total[name]; !ok || quantity.Cmp(value) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
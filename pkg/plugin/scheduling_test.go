@@ -0,0 +1,234 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func schedulingTestNode(name string, labels map[string]string, taints []v1.Taint, cpu string, memory string) v1.Node {
+	return v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       v1.NodeSpec{Taints: taints},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    apires.MustParse(cpu),
+				v1.ResourceMemory: apires.MustParse(memory),
+				v1.ResourcePods:   apires.MustParse("110"),
+			},
+		},
+	}
+}
+
+func schedulingTestPod(name string, cpu string, memory string) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name: "app",
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+					v1.ResourceCPU:    apires.MustParse(cpu),
+					v1.ResourceMemory: apires.MustParse(memory),
+				}},
+			}},
+		},
+	}
+}
+
+// *****************
+// schedulingCheckNodeSelector
+// *****************
+
+func TestSchedulingCheckNodeSelector(t *testing.T) {
+	pod := schedulingTestPod("web", "100m", "64Mi")
+	pod.Spec.NodeSelector = map[string]string{"disktype": "ssd", "zone": "a"}
+
+	tests := []struct {
+		labels   map[string]string
+		expected string
+	}{
+		{map[string]string{"disktype": "ssd", "zone": "a"}, ""},
+		{map[string]string{"disktype": "hdd", "zone": "a"}, "disktype=ssd"},
+		{map[string]string{}, "disktype=ssd,zone=a"},
+	}
+
+	for _, test := range tests {
+		node := schedulingTestNode("node1", test.labels, nil, "1", "1Gi")
+		output := schedulingCheckNodeSelector(pod, node)
+		if output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+}
+
+// *****************
+// schedulingCheckNodeAffinity
+// *****************
+
+func TestSchedulingCheckNodeAffinity(t *testing.T) {
+	pod := schedulingTestPod("web", "100m", "64Mi")
+	pod.Spec.Affinity = &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{MatchExpressions: []v1.NodeSelectorRequirement{
+					{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a", "b"}},
+					{Key: "gpu", Operator: v1.NodeSelectorOpDoesNotExist},
+				}},
+				{MatchExpressions: []v1.NodeSelectorRequirement{
+					{Key: "cores", Operator: v1.NodeSelectorOpGt, Values: []string{"16"}},
+				}},
+			},
+		},
+	}}
+
+	tests := []struct {
+		labels   map[string]string
+		expected string
+	}{
+		{map[string]string{"zone": "a"}, ""},
+		{map[string]string{"zone": "c", "cores": "32"}, ""},
+		{map[string]string{"zone": "b", "gpu": "true"}, "gpu DoesNotExist|cores Gt (16)"},
+		{map[string]string{"zone": "c", "cores": "8"}, "zone In (a,b)|cores Gt (16)"},
+	}
+
+	for _, test := range tests {
+		node := schedulingTestNode("node1", test.labels, nil, "1", "1Gi")
+		output := schedulingCheckNodeAffinity(pod, node)
+		if output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+}
+
+// *****************
+// schedulingCheckTaints
+// *****************
+
+func TestSchedulingCheckTaints(t *testing.T) {
+	pod := schedulingTestPod("web", "100m", "64Mi")
+	pod.Spec.Tolerations = []v1.Toleration{
+		{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "web", Effect: v1.TaintEffectNoSchedule},
+	}
+
+	tests := []struct {
+		taints        []v1.Taint
+		unschedulable bool
+		expected      string
+	}{
+		{[]v1.Taint{{Key: "dedicated", Value: "web", Effect: v1.TaintEffectNoSchedule}}, false, ""},
+		{[]v1.Taint{{Key: "dedicated", Value: "db", Effect: v1.TaintEffectNoSchedule}}, false, "dedicated=db:NoSchedule"},
+		{[]v1.Taint{{Key: "spot", Effect: v1.TaintEffectPreferNoSchedule}}, false, ""},
+		{[]v1.Taint{{Key: "node.kubernetes.io/not-ready", Effect: v1.TaintEffectNoExecute}}, false, "node.kubernetes.io/not-ready:NoExecute"},
+		{nil, true, "node.kubernetes.io/unschedulable:NoSchedule"},
+	}
+
+	for _, test := range tests {
+		node := schedulingTestNode("node1", nil, test.taints, "1", "1Gi")
+		node.Spec.Unschedulable = test.unschedulable
+		output := schedulingCheckTaints(pod, node)
+		if output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+}
+
+// *****************
+// schedulingCheckResources
+// *****************
+
+func TestSchedulingCheckResources(t *testing.T) {
+	node := schedulingTestNode("node1", nil, nil, "2", "4Gi")
+	running := []v1.Pod{
+		schedulingTestPod("running1", "1", "1Gi"),
+		schedulingTestPod("running2", "500m", "1Gi"),
+	}
+
+	tests := []struct {
+		pod      v1.Pod
+		expected string
+	}{
+		{schedulingTestPod("small", "500m", "1Gi"), ""},
+		{schedulingTestPod("cpu", "600m", "1Gi"), "cpu:600m>500m"},
+		{schedulingTestPod("both", "1", "3Gi"), "cpu:1>500m,memory:3Gi>2Gi"},
+	}
+
+	for _, test := range tests {
+		output := schedulingCheckResources(test.pod, node, running)
+		if output != test.expected {
+			t.Errorf("Output %s not equal to expected %s, for pod %s", output, test.expected, test.pod.Name)
+		}
+	}
+}
+
+// *****************
+// schedulingContainerResources
+// *****************
+
+func TestSchedulingContainerResources(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	limits := func(memory string) v1.ResourceRequirements {
+		return v1.ResourceRequirements{Limits: v1.ResourceList{v1.ResourceMemory: apires.MustParse(memory)}}
+	}
+
+	tests := []struct {
+		name     string
+		setup    string
+		app      string
+		expected string
+	}{
+		// setup runs before the sidecar starts so only needs room for itself
+		{"largest init container", "256Mi", "128Mi", "256Mi"},
+		// migrate runs alongside the sidecar, 64Mi+32Mi
+		{"init container with sidecar", "16Mi", "32Mi", "96Mi"},
+		{"sidecar added to containers", "16Mi", "160Mi", "192Mi"},
+	}
+
+	for _, test := range tests {
+		pod := v1.Pod{Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{Name: "setup", Resources: limits(test.setup)},
+				{Name: "proxy", RestartPolicy: &always, Resources: limits("32Mi")},
+				{Name: "migrate", Resources: limits("64Mi")},
+			},
+			Containers: []v1.Container{{Name: "app", Resources: limits(test.app)}},
+		}}
+
+		output := schedulingContainerResources(pod, func(res v1.ResourceRequirements) v1.ResourceList {
+			return res.Limits
+		})
+		if output.Memory().Cmp(apires.MustParse(test.expected)) != 0 {
+			t.Errorf("%s: output %s not equal to expected %s", test.name, output.Memory().String(), test.expected)
+		}
+	}
+}
+
+// *****************
+// schedulingPodRequests
+// *****************
+
+func TestSchedulingPodRequests(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+
+	pod := schedulingTestPod("web", "200m", "64Mi")
+	pod.Spec.InitContainers = []v1.Container{
+		{Name: "sidecar", RestartPolicy: &always, Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: apires.MustParse("100m")}}},
+		{Name: "migrate", Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: apires.MustParse("500m")}}},
+	}
+	pod.Spec.Overhead = v1.ResourceList{v1.ResourceCPU: apires.MustParse("50m")}
+
+	output := schedulingPodRequests(pod)
+	cpu := output[v1.ResourceCPU]
+	// the migrate init container runs alongside the sidecar, 500m+100m is larger than 200m+100m
+	if cpu.MilliValue() != 650 {
+		t.Errorf("Output cpu %dm not equal to expected 650m", cpu.MilliValue())
+	}
+
+	pod.Spec.Resources = &v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: apires.MustParse("1")}}
+	output = schedulingPodRequests(pod)
+	cpu = output[v1.ResourceCPU]
+	if cpu.MilliValue() != 1050 {
+		t.Errorf("Output cpu %dm not equal to expected 1050m with pod level resources", cpu.MilliValue())
+	}
+}
@@ -1,17 +1,35 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	psaapi "k8s.io/pod-security-admission/api"
+	psapolicy "k8s.io/pod-security-admission/policy"
 )
 
 var securityShort = "Shows details of configured container security settings"
 
 var securityDescription = ` View SecurityContext configuration that has been applied to the containers. Shows 
 runAsUser and runAsGroup fields among others.
+
+Values are shown as the container sees them, settings made in the container securityContext override
+the same settings made in the pod securityContext. Use --effective to also show the pod only fsGroup,
+supplementalGroups and sysctls settings along with a SOURCE column, the source lists each inherited
+setting followed by P when the value came from the pod or C when it was set on the container.
+
+Use --isolation to show the host namespaces shared with the pod along with the seccomp, AppArmor,
+procMount and Windows hostProcess settings, risky settings are coloured red. The legacy AppArmor
+annotation is used when no AppArmor profile field is set.
+
+Use --pss baseline or --pss restricted to evaluate each container against the Pod Security Standards
+using the same checks as the pod security admission controller. The LEVEL column shows the highest
+level the container meets and VIOLATIONS lists the controls that fail the requested level, pod level
+settings such as host namespaces and volumes are included in the result of every container in the pod.
 `
 
 var securityExample = `  # List container security info from pods
@@ -39,7 +57,17 @@ var securityExample = `  # List container security info from pods
   %[1]s security -l app=web
 
   # List container security info from all pods where the pod label app is either web or mail
-  %[1]s security -l "app in (web,mail)"`
+  %[1]s security -l "app in (web,mail)"
+
+  # List container security info along with the pod only settings and where each value came from
+  %[1]s security --effective
+
+  # List the host namespace, seccomp and AppArmor settings applied to each container
+  %[1]s security --isolation
+
+  # List the containers that would be rejected if the namespace was labeled with the restricted
+  # pod security standard
+  %[1]s security --pss restricted --match 'LEVEL!=restricted'`
 
 // list details of configured liveness readiness and startup security
 func Security(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
@@ -78,6 +106,37 @@ func Security(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 		loopinfo.ShowSELinuxOptions = true
 	}
 
+	if cmd.Flag("effective").Value.String() == "true" {
+		log.Debug("loopinfo.ShowEffective = true")
+		loopinfo.ShowEffective = true
+	}
+
+	if cmd.Flag("isolation").Value.String() == "true" {
+		log.Debug("loopinfo.ShowIsolation = true")
+		if loopinfo.ShowSELinuxOptions {
+			return errors.New("--isolation can not be used with --selinux")
+		}
+		loopinfo.ShowIsolation = true
+	}
+
+	if pss := cmd.Flag("pss").Value.String(); len(pss) > 0 {
+		log.Debug("loopinfo.PSSLevel =", pss)
+		if loopinfo.ShowSELinuxOptions {
+			return errors.New("--pss can not be used with --selinux")
+		}
+
+		level, err := psaapi.ParseLevel(pss)
+		if err != nil || level == psaapi.LevelPrivileged {
+			return errors.New("--pss must be one of baseline or restricted")
+		}
+		loopinfo.PSSLevel = level
+
+		loopinfo.evaluator, err = psapolicy.NewEvaluator(psapolicy.DefaultChecks())
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := builder.Build(&loopinfo); err != nil {
 		return err
 	}
@@ -93,6 +152,10 @@ func Security(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 
 type security struct {
 	ShowSELinuxOptions bool
+	ShowEffective      bool
+	ShowIsolation      bool
+	PSSLevel           psaapi.Level
+	evaluator          psapolicy.Evaluator
 }
 
 func (s *security) Headers() []string {
@@ -102,6 +165,7 @@ func (s *security) Headers() []string {
 			"ROLE",
 			"TYPE",
 			"LEVEL",
+			"SOURCE",
 		}
 	} else {
 		return []string{
@@ -111,6 +175,24 @@ func (s *security) Headers() []string {
 			"RUN_AS_NON_ROOT",
 			"RUN_AS_USER",
 			"RUN_AS_GROUP",
+			"LEVEL",
+			"VIOLATIONS",
+			"FS_GROUP",
+			"FS_GROUP_CHANGE_POLICY",
+			"SUPPLEMENTAL_GROUPS",
+			"SUPPLEMENTAL_GROUPS_POLICY",
+			"SYSCTLS",
+			"SOURCE",
+			"HOST_NETWORK",
+			"HOST_PID",
+			"HOST_IPC",
+			"HOST_USERS",
+			"SECCOMP",
+			"SECCOMP_PROFILE",
+			"APPARMOR",
+			"APPARMOR_PROFILE",
+			"PROC_MOUNT",
+			"HOST_PROCESS",
 		}
 	}
 }
@@ -124,17 +206,58 @@ func (s *security) BuildEphemeralContainerStatus(container v1.ContainerStatus, i
 }
 
 func (s *security) HideColumns(info BuilderInformation) []int {
-	return []int{}
+	var hideColumns []int
+
+	if s.ShowSELinuxOptions {
+		if !s.ShowEffective {
+			hideColumns = append(hideColumns, 4)
+		}
+		return hideColumns
+	}
+
+	if s.evaluator == nil {
+		hideColumns = append(hideColumns, 6, 7)
+	}
+
+	if !s.ShowEffective {
+		hideColumns = append(hideColumns, 8, 9, 10, 11, 12, 13)
+	}
+
+	if !s.ShowIsolation {
+		hideColumns = append(hideColumns, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23)
+	}
+
+	return hideColumns
 }
 
 func (s *security) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	var rowOut []Cell
+	rowOut := make([]Cell, len(s.Headers()))
 
-	if s.ShowSELinuxOptions {
-		rowOut = make([]Cell, 4)
-	} else {
-		rowOut = make([]Cell, 6)
+	if s.ShowSELinuxOptions || s.evaluator == nil {
+		return rowOut, nil
 	}
+
+	// the parent only meets the lowest level met by any of its children
+	level := psaapi.LevelRestricted
+	violationList := []string{}
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		if len(row[6].text) > 0 && pssLevelRank(psaapi.Level(row[6].text)) < pssLevelRank(level) {
+			level = psaapi.Level(row[6].text)
+		}
+		if len(row[7].text) > 0 {
+			for _, violation := range strings.Split(row[7].text, ",") {
+				if !seen[violation] {
+					seen[violation] = true
+					violationList = append(violationList, violation)
+				}
+			}
+		}
+	}
+
+	rowOut[6] = s.pssLevelCell(level)
+	rowOut[7] = NewCellText(strings.Join(violationList, ","))
+
 	return rowOut, nil
 }
 
@@ -166,20 +289,13 @@ func (s *security) securityBuildRow(info BuilderInformation, csc *v1.SecurityCon
 	ranr := Cell{}
 	rau := Cell{}
 	rag := Cell{}
+	fsg := Cell{}
+	fsgcp := Cell{}
+	sg := Cell{}
+	sgp := Cell{}
+	sysctl := Cell{}
 
-	if psc != nil {
-		if psc.RunAsNonRoot != nil {
-			ranr = NewCellText(fmt.Sprintf("%t", *psc.RunAsNonRoot))
-		}
-
-		if psc.RunAsUser != nil {
-			rau = NewCellInt(fmt.Sprintf("%d", *psc.RunAsUser), *psc.RunAsUser)
-		}
-
-		if psc.RunAsGroup != nil {
-			rag = NewCellInt(fmt.Sprintf("%d", *psc.RunAsGroup), *psc.RunAsGroup)
-		}
-	}
+	esc := newEffectiveSecurityContext(psc, csc)
 
 	if csc != nil {
 		if csc.AllowPrivilegeEscalation != nil {
@@ -193,17 +309,48 @@ func (s *security) securityBuildRow(info BuilderInformation, csc *v1.SecurityCon
 		if csc.ReadOnlyRootFilesystem != nil {
 			rorfs = NewCellText(fmt.Sprintf("%t", *csc.ReadOnlyRootFilesystem))
 		}
+	}
 
-		if csc.RunAsNonRoot != nil {
-			ranr = NewCellText(fmt.Sprintf("%t", *csc.RunAsNonRoot))
+	if esc.runAsNonRoot != nil {
+		ranr = NewCellText(fmt.Sprintf("%t", *esc.runAsNonRoot))
+	}
+
+	if esc.runAsUser != nil {
+		rau = NewCellInt(fmt.Sprintf("%d", *esc.runAsUser), *esc.runAsUser)
+	}
+
+	if esc.runAsGroup != nil {
+		rag = NewCellInt(fmt.Sprintf("%d", *esc.runAsGroup), *esc.runAsGroup)
+	}
+
+	// these fields can only be set on the pod
+	if psc != nil {
+		if psc.FSGroup != nil {
+			fsg = NewCellInt(fmt.Sprintf("%d", *psc.FSGroup), *psc.FSGroup)
 		}
 
-		if csc.RunAsUser != nil {
-			rau = NewCellInt(fmt.Sprintf("%d", *csc.RunAsUser), *csc.RunAsUser)
+		if psc.FSGroupChangePolicy != nil {
+			fsgcp = NewCellText(string(*psc.FSGroupChangePolicy))
 		}
 
-		if csc.RunAsGroup != nil {
-			rag = NewCellInt(fmt.Sprintf("%d", *psc.RunAsGroup), *csc.RunAsGroup)
+		if len(psc.SupplementalGroups) > 0 {
+			var groupList []string
+			for _, group := range psc.SupplementalGroups {
+				groupList = append(groupList, fmt.Sprintf("%d", group))
+			}
+			sg = NewCellText(strings.Join(groupList, ","))
+		}
+
+		if psc.SupplementalGroupsPolicy != nil {
+			sgp = NewCellText(string(*psc.SupplementalGroupsPolicy))
+		}
+
+		if len(psc.Sysctls) > 0 {
+			var sysctlList []string
+			for _, sysctl := range psc.Sysctls {
+				sysctlList = append(sysctlList, sysctl.Name+"="+sysctl.Value)
+			}
+			sysctl = NewCellText(strings.Join(sysctlList, ","))
 		}
 	}
 
@@ -220,6 +367,24 @@ func (s *security) securityBuildRow(info BuilderInformation, csc *v1.SecurityCon
 		rag,
 	)
 
+	cellList = append(cellList, s.pssBuildCells(info)...)
+
+	cellList = append(cellList,
+		fsg,
+		fsgcp,
+		sg,
+		sgp,
+		sysctl,
+	)
+
+	if s.ShowIsolation {
+		cellList = append(cellList, NewCellText(esc.sourceString("runAsNonRoot", "runAsUser", "runAsGroup", "seccompProfile", "appArmorProfile", "hostProcess")))
+	} else {
+		cellList = append(cellList, NewCellText(esc.sourceString("runAsNonRoot", "runAsUser", "runAsGroup")))
+	}
+
+	cellList = append(cellList, s.isolationBuildCells(info, csc, esc)...)
+
 	return cellList
 
 }
@@ -231,45 +396,24 @@ func (s *security) seLinuxBuildRow(info BuilderInformation, csc *v1.SecurityCont
 	seType := Cell{}
 	seUser := Cell{}
 
-	if psc != nil {
-		if psc.SELinuxOptions != nil {
-			pselinux := psc.SELinuxOptions
-			if len(pselinux.Level) > 0 {
-				seLevel = NewCellText(pselinux.Level)
-			}
-
-			if len(pselinux.Role) > 0 {
-				seRole = NewCellText(pselinux.Role)
-			}
-
-			if len(pselinux.Type) > 0 {
-				seType = NewCellText(pselinux.Type)
-			}
+	esc := newEffectiveSecurityContext(psc, csc)
 
-			if len(pselinux.User) > 0 {
-				seUser = NewCellText(pselinux.User)
-			}
+	if esc.seLinuxOptions != nil {
+		selinux := esc.seLinuxOptions
+		if len(selinux.Level) > 0 {
+			seLevel = NewCellText(selinux.Level)
 		}
-	}
 
-	if csc != nil {
-		if csc.SELinuxOptions != nil {
-			cselinux := psc.SELinuxOptions
-			if len(cselinux.Level) > 0 {
-				seLevel = NewCellText(cselinux.Level)
-			}
-
-			if len(cselinux.Role) > 0 {
-				seRole = NewCellText(cselinux.Role)
-			}
+		if len(selinux.Role) > 0 {
+			seRole = NewCellText(selinux.Role)
+		}
 
-			if len(cselinux.Type) > 0 {
-				seType = NewCellText(cselinux.Type)
-			}
+		if len(selinux.Type) > 0 {
+			seType = NewCellText(selinux.Type)
+		}
 
-			if len(cselinux.User) > 0 {
-				seUser = NewCellText(cselinux.User)
-			}
+		if len(selinux.User) > 0 {
+			seUser = NewCellText(selinux.User)
 		}
 	}
 
@@ -282,6 +426,7 @@ func (s *security) seLinuxBuildRow(info BuilderInformation, csc *v1.SecurityCont
 		seRole,
 		seType,
 		seLevel,
+		NewCellText(esc.sourceString("seLinuxOptions")),
 	)
 
 	return cellList
@@ -290,3 +435,271 @@ func (s *security) seLinuxBuildRow(info BuilderInformation, csc *v1.SecurityCont
 func (s *security) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
 	return [][]Cell{}, nil
 }
+
+// pssBuildCells evaluates the container against the pod security standards, returns the LEVEL and
+// VIOLATIONS cells
+func (s *security) pssBuildCells(info BuilderInformation) []Cell {
+	if s.evaluator == nil {
+		return []Cell{{}, {}}
+	}
+
+	pod := info.Data.pod
+	spec := pssContainerSpec(pod.Spec, info.ContainerType, info.Name)
+
+	// restricted includes all the baseline checks so the first level to fail sets the level met
+	level := psaapi.LevelRestricted
+	var violations []string
+	for _, checkLevel := range []psaapi.Level{psaapi.LevelBaseline, psaapi.LevelRestricted} {
+		results := s.evaluator.EvaluatePod(psaapi.LevelVersion{Level: checkLevel, Version: psaapi.LatestVersion()}, &pod.ObjectMeta, &spec)
+		aggregate := psapolicy.AggregateCheckResults(results)
+		if aggregate.Allowed {
+			continue
+		}
+
+		if level == psaapi.LevelRestricted {
+			if checkLevel == psaapi.LevelBaseline {
+				level = psaapi.LevelPrivileged
+			} else {
+				level = psaapi.LevelBaseline
+			}
+		}
+
+		if checkLevel == s.PSSLevel {
+			violations = aggregate.ForbiddenReasons
+		}
+	}
+
+	return []Cell{
+		s.pssLevelCell(level),
+		NewCellText(strings.Join(violations, ",")),
+	}
+}
+
+// pssLevelCell colours the level depending on if it meets the requested level
+func (s *security) pssLevelCell(level psaapi.Level) Cell {
+	return NewCellColourText(setColourBoolean(pssLevelRank(level) >= pssLevelRank(s.PSSLevel)), string(level))
+}
+
+// pssContainerSpec returns a copy of the pod spec that only contains the named container, so any
+// violations found belong to either the container or the pod its running in
+func pssContainerSpec(podSpec v1.PodSpec, containerType string, name string) v1.PodSpec {
+	spec := *podSpec.DeepCopy()
+	spec.InitContainers = nil
+	spec.Containers = nil
+	spec.EphemeralContainers = nil
+
+	switch containerType {
+	case TypeIDInitContainer, TypeIDSidecarContainer:
+		for _, container := range podSpec.InitContainers {
+			if container.Name == name {
+				spec.InitContainers = append(spec.InitContainers, *container.DeepCopy())
+			}
+		}
+	case TypeIDEphemeralContainer:
+		for _, container := range podSpec.EphemeralContainers {
+			if container.Name == name {
+				spec.EphemeralContainers = append(spec.EphemeralContainers, *container.DeepCopy())
+			}
+		}
+	default:
+		for _, container := range podSpec.Containers {
+			if container.Name == name {
+				spec.Containers = append(spec.Containers, *container.DeepCopy())
+			}
+		}
+	}
+
+	return spec
+}
+
+// pssLevelRank orders the levels from least to most restrictive
+func pssLevelRank(level psaapi.Level) int {
+	switch level {
+	case psaapi.LevelBaseline:
+		return 1
+	case psaapi.LevelRestricted:
+		return 2
+	}
+	return 0
+}
+
+// isolationBuildCells returns the host namespace, seccomp, apparmor, procMount and hostProcess cells,
+// settings that weaken the isolation of the container are coloured bad
+func (s *security) isolationBuildCells(info BuilderInformation, csc *v1.SecurityContext, esc effectiveSecurityContext) []Cell {
+	hostNetwork := Cell{}
+	hostPID := Cell{}
+	hostIPC := Cell{}
+	hostUsers := Cell{}
+	seccomp := Cell{}
+	seccompProfile := Cell{}
+	apparmor := Cell{}
+	apparmorProfile := Cell{}
+	procMount := Cell{}
+	hostProcess := Cell{}
+
+	podSpec := info.Data.pod.Spec
+	if podSpec.HostNetwork {
+		hostNetwork = NewCellColourText(setColourBoolean(false), "true")
+	}
+	if podSpec.HostPID {
+		hostPID = NewCellColourText(setColourBoolean(false), "true")
+	}
+	if podSpec.HostIPC {
+		hostIPC = NewCellColourText(setColourBoolean(false), "true")
+	}
+	if podSpec.HostUsers != nil {
+		// hostUsers false runs the pod in its own user namespace
+		hostUsers = NewCellColourText(setColourBoolean(!*podSpec.HostUsers), fmt.Sprintf("%t", *podSpec.HostUsers))
+	}
+
+	if esc.seccompProfile != nil {
+		seccomp = NewCellColourText(setColourBoolean(esc.seccompProfile.Type != v1.SeccompProfileTypeUnconfined), string(esc.seccompProfile.Type))
+		if esc.seccompProfile.LocalhostProfile != nil {
+			seccompProfile = NewCellText(*esc.seccompProfile.LocalhostProfile)
+		}
+	}
+
+	if esc.appArmorProfile != nil {
+		apparmor = NewCellColourText(setColourBoolean(esc.appArmorProfile.Type != v1.AppArmorProfileTypeUnconfined), string(esc.appArmorProfile.Type))
+		if esc.appArmorProfile.LocalhostProfile != nil {
+			apparmorProfile = NewCellText(*esc.appArmorProfile.LocalhostProfile)
+		}
+	} else if annotation, ok := info.Data.pod.Annotations[v1.DeprecatedAppArmorBetaContainerAnnotationKeyPrefix+info.Name]; ok {
+		profileType, profile := appArmorFromAnnotation(annotation)
+		apparmor = NewCellColourText(setColourBoolean(profileType != v1.AppArmorProfileTypeUnconfined), string(profileType))
+		if len(profile) > 0 {
+			apparmorProfile = NewCellText(profile)
+		}
+	}
+
+	if csc != nil && csc.ProcMount != nil {
+		procMount = NewCellColourText(setColourBoolean(*csc.ProcMount != v1.UnmaskedProcMount), string(*csc.ProcMount))
+	}
+
+	if esc.hostProcess != nil {
+		hostProcess = NewCellColourText(setColourBoolean(!*esc.hostProcess), fmt.Sprintf("%t", *esc.hostProcess))
+	}
+
+	return []Cell{
+		hostNetwork,
+		hostPID,
+		hostIPC,
+		hostUsers,
+		seccomp,
+		seccompProfile,
+		apparmor,
+		apparmorProfile,
+		procMount,
+		hostProcess,
+	}
+}
+
+// appArmorFromAnnotation converts the legacy apparmor annotation value into the matching profile type
+func appArmorFromAnnotation(value string) (v1.AppArmorProfileType, string) {
+	switch {
+	case value == v1.DeprecatedAppArmorBetaProfileRuntimeDefault:
+		return v1.AppArmorProfileTypeRuntimeDefault, ""
+	case value == v1.DeprecatedAppArmorBetaProfileNameUnconfined:
+		return v1.AppArmorProfileTypeUnconfined, ""
+	case strings.HasPrefix(value, v1.DeprecatedAppArmorBetaProfileNamePrefix):
+		return v1.AppArmorProfileTypeLocalhost, strings.TrimPrefix(value, v1.DeprecatedAppArmorBetaProfileNamePrefix)
+	}
+
+	return v1.AppArmorProfileType(value), ""
+}
+
+// effectiveSecurityContext holds the settings that can be made on both the pod and the container
+// after the container settings have been applied over the pod settings
+type effectiveSecurityContext struct {
+	runAsUser       *int64
+	runAsGroup      *int64
+	runAsNonRoot    *bool
+	seLinuxOptions  *v1.SELinuxOptions
+	seccompProfile  *v1.SeccompProfile
+	appArmorProfile *v1.AppArmorProfile
+	hostProcess     *bool
+	source          map[string]string // setting name to TypeIDPod or TypeIDContainer
+}
+
+// newEffectiveSecurityContext merges the pod and container security contexts using the same
+// precedence as the kubelet, container settings replace pod settings
+func newEffectiveSecurityContext(psc *v1.PodSecurityContext, csc *v1.SecurityContext) effectiveSecurityContext {
+	esc := effectiveSecurityContext{source: make(map[string]string)}
+
+	if psc != nil {
+		if psc.RunAsUser != nil {
+			esc.runAsUser = psc.RunAsUser
+			esc.source["runAsUser"] = TypeIDPod
+		}
+		if psc.RunAsGroup != nil {
+			esc.runAsGroup = psc.RunAsGroup
+			esc.source["runAsGroup"] = TypeIDPod
+		}
+		if psc.RunAsNonRoot != nil {
+			esc.runAsNonRoot = psc.RunAsNonRoot
+			esc.source["runAsNonRoot"] = TypeIDPod
+		}
+		if psc.SELinuxOptions != nil {
+			esc.seLinuxOptions = psc.SELinuxOptions
+			esc.source["seLinuxOptions"] = TypeIDPod
+		}
+		if psc.SeccompProfile != nil {
+			esc.seccompProfile = psc.SeccompProfile
+			esc.source["seccompProfile"] = TypeIDPod
+		}
+		if psc.AppArmorProfile != nil {
+			esc.appArmorProfile = psc.AppArmorProfile
+			esc.source["appArmorProfile"] = TypeIDPod
+		}
+		if psc.WindowsOptions != nil && psc.WindowsOptions.HostProcess != nil {
+			esc.hostProcess = psc.WindowsOptions.HostProcess
+			esc.source["hostProcess"] = TypeIDPod
+		}
+	}
+
+	if csc != nil {
+		if csc.RunAsUser != nil {
+			esc.runAsUser = csc.RunAsUser
+			esc.source["runAsUser"] = TypeIDContainer
+		}
+		if csc.RunAsGroup != nil {
+			esc.runAsGroup = csc.RunAsGroup
+			esc.source["runAsGroup"] = TypeIDContainer
+		}
+		if csc.RunAsNonRoot != nil {
+			esc.runAsNonRoot = csc.RunAsNonRoot
+			esc.source["runAsNonRoot"] = TypeIDContainer
+		}
+		if csc.SELinuxOptions != nil {
+			esc.seLinuxOptions = csc.SELinuxOptions
+			esc.source["seLinuxOptions"] = TypeIDContainer
+		}
+		if csc.SeccompProfile != nil {
+			esc.seccompProfile = csc.SeccompProfile
+			esc.source["seccompProfile"] = TypeIDContainer
+		}
+		if csc.AppArmorProfile != nil {
+			esc.appArmorProfile = csc.AppArmorProfile
+			esc.source["appArmorProfile"] = TypeIDContainer
+		}
+		if csc.WindowsOptions != nil && csc.WindowsOptions.HostProcess != nil {
+			esc.hostProcess = csc.WindowsOptions.HostProcess
+			esc.source["hostProcess"] = TypeIDContainer
+		}
+	}
+
+	return esc
+}
+
+// sourceString lists where each of the named settings came from, unset settings are skipped
+func (e effectiveSecurityContext) sourceString(names ...string) string {
+	var sourceList []string
+
+	for _, name := range names {
+		if source, ok := e.source[name]; ok {
+			sourceList = append(sourceList, name+":"+source)
+		}
+	}
+
+	return strings.Join(sourceList, ",")
+}
@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"strconv"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	psaapi "k8s.io/pod-security-admission/api"
+	psapolicy "k8s.io/pod-security-admission/policy"
+)
+
+// *****************
+// pssBuildCells
+// *****************
+
+type pssBuildCellsTest struct {
+	arg1               v1.PodSpec
+	arg2               string
+	expectedLevel      string
+	expectedViolations string
+}
+
+var pssTrue = true
+var pssFalse = false
+
+var pssRestrictedContext = &v1.SecurityContext{
+	AllowPrivilegeEscalation: &pssFalse,
+	RunAsNonRoot:             &pssTrue,
+	Capabilities:             &v1.Capabilities{Drop: []v1.Capability{"ALL"}},
+	SeccompProfile:           &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault},
+}
+
+var pssBuildCellsTests = []pssBuildCellsTest{
+	{v1.PodSpec{Containers: []v1.Container{{Name: "app", SecurityContext: pssRestrictedContext}}}, "app", "restricted", ""},
+	{v1.PodSpec{Containers: []v1.Container{{Name: "app"}}}, "app", "baseline", "allowPrivilegeEscalation != false,unrestricted capabilities,runAsNonRoot != true,seccompProfile"},
+	{v1.PodSpec{Containers: []v1.Container{
+		{Name: "app", SecurityContext: pssRestrictedContext},
+		{Name: "debug", SecurityContext: &v1.SecurityContext{Privileged: &pssTrue}},
+	}}, "app", "restricted", ""},
+	{v1.PodSpec{Containers: []v1.Container{
+		{Name: "app", SecurityContext: pssRestrictedContext},
+		{Name: "debug", SecurityContext: &v1.SecurityContext{Privileged: &pssTrue}},
+	}}, "debug", "privileged", "privileged,allowPrivilegeEscalation != false,unrestricted capabilities,runAsNonRoot != true,seccompProfile"},
+	{v1.PodSpec{HostNetwork: true, Containers: []v1.Container{{Name: "app", SecurityContext: pssRestrictedContext}}}, "app", "privileged", "host namespaces"},
+}
+
+func TestPSSBuildCells(t *testing.T) {
+	evaluator, err := psapolicy.NewEvaluator(psapolicy.DefaultChecks())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec := security{PSSLevel: psaapi.LevelRestricted, evaluator: evaluator}
+
+	for _, test := range pssBuildCellsTests {
+		info := BuilderInformation{ContainerType: TypeIDContainer, Name: test.arg2}
+		info.Data.pod = v1.Pod{Spec: test.arg1}
+
+		output := sec.pssBuildCells(info)
+		if output[0].text != test.expectedLevel {
+			t.Errorf("Output %s not equal to expected %s", output[0].text, test.expectedLevel)
+		}
+		if output[1].text != test.expectedViolations {
+			t.Errorf("Output %s not equal to expected %s", output[1].text, test.expectedViolations)
+		}
+	}
+}
+
+// *****************
+// newEffectiveSecurityContext
+// *****************
+
+type effectiveSecurityContextTest struct {
+	arg1           *v1.PodSecurityContext
+	arg2           *v1.SecurityContext
+	expectedUser   string
+	expectedGroup  string
+	expectedSource string
+}
+
+var escUser1000 = int64(1000)
+var escUser2000 = int64(2000)
+var escGroup3000 = int64(3000)
+
+var effectiveSecurityContextTests = []effectiveSecurityContextTest{
+	{nil, nil, "", "", ""},
+	{&v1.PodSecurityContext{RunAsUser: &escUser1000, RunAsGroup: &escGroup3000}, nil, "1000", "3000", "runAsUser:P,runAsGroup:P"},
+	{&v1.PodSecurityContext{RunAsUser: &escUser1000, RunAsGroup: &escGroup3000}, &v1.SecurityContext{RunAsUser: &escUser2000}, "2000", "3000", "runAsUser:C,runAsGroup:P"},
+	{nil, &v1.SecurityContext{RunAsGroup: &escGroup3000, RunAsNonRoot: &pssTrue}, "", "3000", "runAsNonRoot:C,runAsGroup:C"},
+}
+
+func TestNewEffectiveSecurityContext(t *testing.T) {
+
+	for _, test := range effectiveSecurityContextTests {
+		output := newEffectiveSecurityContext(test.arg1, test.arg2)
+
+		user := ""
+		if output.runAsUser != nil {
+			user = strconv.FormatInt(*output.runAsUser, 10)
+		}
+		if user != test.expectedUser {
+			t.Errorf("Output user %s not equal to expected %s", user, test.expectedUser)
+		}
+
+		group := ""
+		if output.runAsGroup != nil {
+			group = strconv.FormatInt(*output.runAsGroup, 10)
+		}
+		if group != test.expectedGroup {
+			t.Errorf("Output group %s not equal to expected %s", group, test.expectedGroup)
+		}
+
+		source := output.sourceString("runAsNonRoot", "runAsUser", "runAsGroup")
+		if source != test.expectedSource {
+			t.Errorf("Output source %s not equal to expected %s", source, test.expectedSource)
+		}
+	}
+}
+
+func TestSeLinuxOptionsReplacedByContainer(t *testing.T) {
+	psc := &v1.PodSecurityContext{SELinuxOptions: &v1.SELinuxOptions{User: "pod_u", Level: "s0:c1"}}
+	csc := &v1.SecurityContext{SELinuxOptions: &v1.SELinuxOptions{Type: "container_t"}}
+
+	output := newEffectiveSecurityContext(psc, csc)
+	if output.seLinuxOptions.Type != "container_t" || len(output.seLinuxOptions.User) > 0 || len(output.seLinuxOptions.Level) > 0 {
+		t.Errorf("Output %v not equal to expected container options", *output.seLinuxOptions)
+	}
+}
+
+// *****************
+// appArmorFromAnnotation
+// *****************
+
+type appArmorFromAnnotationTest struct {
+	arg1            string
+	expectedType    v1.AppArmorProfileType
+	expectedProfile string
+}
+
+var appArmorFromAnnotationTests = []appArmorFromAnnotationTest{
+	{"runtime/default", v1.AppArmorProfileTypeRuntimeDefault, ""},
+	{"unconfined", v1.AppArmorProfileTypeUnconfined, ""},
+	{"localhost/k8s-deny-write", v1.AppArmorProfileTypeLocalhost, "k8s-deny-write"},
+}
+
+func TestAppArmorFromAnnotation(t *testing.T) {
+
+	for _, test := range appArmorFromAnnotationTests {
+		profileType, profile := appArmorFromAnnotation(test.arg1)
+		if profileType != test.expectedType {
+			t.Errorf("Output %s not equal to expected %s", profileType, test.expectedType)
+		}
+		if profile != test.expectedProfile {
+			t.Errorf("Output %s not equal to expected %s", profile, test.expectedProfile)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package plugin
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var startupShort = "Show a timeline of each phase of pod startup"
+
+var startupDescription = ` Prints one row for each phase a pod goes through between being created and becoming ready,
+the phases are pod creation, scheduling, pulling each image, running each init container, starting
+each container and the pod becoming Ready. START and END are shown as the offset from when the pod
+was created and DURATION is the time taken by the phase.
+
+Image pull times are read from the pods Pulling and Pulled events so are only available when
+connected to a cluster and only while the events are still retained. The Ready time is taken from
+the last transition of the Ready condition, if a pod has become unready since it started the Ready
+phase will show the most recent transition.
+
+The --gantt flag adds a chart of the phases to the output, each pod is scaled to the width of the
+chart so long and short phases can be compared.`
+
+var startupExample = `  # Show the startup timeline of all pods in the current namespace
+  %[1]s startup
+
+  # Show the startup timeline of a single pod as a chart
+  %[1]s startup my-pod-4jh36 --gantt
+
+  # List the image pulls that took longer than 30 seconds
+  %[1]s startup --match 'PHASE==pull,DURATION>30000'
+
+  # Show how long each pod took to become ready grouped by owner
+  %[1]s startup --tree`
+
+// width in characters of the gantt chart
+const startupGanttWidth = 40
+
+const (
+	startupPhaseCreated   = "created"
+	startupPhaseScheduled = "scheduled"
+	startupPhasePull      = "pull"
+	startupPhaseInit      = "init"
+	startupPhaseStart     = "start"
+	startupPhaseReady     = "ready"
+)
+
+// matches the pull time in the Pulled event message, Successfully pulled image "x" in 1.5s ...
+var startupPulledRegexp = regexp.MustCompile(`pulled image .* in ([0-9.]+(?:[a-zµ]+[0-9.]+)*[a-zµ]+)`)
+
+func Startup(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Startup"}
+	log.Debug("Start")
+
+	loopinfo := startup{}
+	builder := RowBuilder{}
+	builder.DontListContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	if cmd.Flag("gantt").Value.String() == "true" {
+		loopinfo.ShowGantt = true
+	}
+
+	// events can only be read from a cluster
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+	if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
+		loopinfo.Connection = &connect
+	}
+
+	table := Table{}
+	table.ColourOutput = commonFlagList.outputAsColour
+	table.CustomColours = commonFlagList.useTheseColours
+
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type startup struct {
+	Connection *Connector // nil when the pods are read from a file
+	ShowGantt  bool
+}
+
+// startupPhase is a single step in the pod startup, end is zero while the phase is still running
+type startupPhase struct {
+	phase string
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+func (s *startup) Headers() []string {
+	return []string{
+		"PHASE", "NAME", "START", "END", "DURATION", "GANTT",
+	}
+}
+
+func (s *startup) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *startup) BuildEphemeralContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *startup) HideColumns(info BuilderInformation) []int {
+	if !s.ShowGantt {
+		return []int{5}
+	}
+	return []int{}
+}
+
+// BuildBranch shows the time each pod took to become ready, owners show the slowest pod
+func (s *startup) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, len(s.Headers()))
+	for i := range rowOut {
+		rowOut[i] = NewCellText("")
+	}
+
+	var slowest int64 = -1
+	for _, r := range rows {
+		if info.TypeName == TypeNamePod {
+			// the end of the ready phase is the time taken for the whole pod
+			if r[0].text == startupPhaseReady && len(r[3].text) > 0 {
+				slowest = r[3].number
+			}
+			continue
+		}
+		if len(r[4].text) > 0 && r[4].number > slowest {
+			slowest = r[4].number
+		}
+	}
+
+	if slowest >= 0 {
+		rowOut[0] = NewCellText(startupPhaseReady)
+		rowOut[4] = startupDurationCell(time.Duration(slowest) * time.Millisecond)
+	}
+
+	return rowOut, nil
+}
+
+func (s *startup) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *startup) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *startup) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
+	var events []v1.Event
+	if s.Connection != nil {
+		events = s.Connection.GetPodEvents(pod)
+	}
+
+	created := pod.CreationTimestamp.Time
+	phases := startupPhases(pod, events)
+	gantt := startupGantt(created, phases, time.Now(), startupGanttWidth)
+
+	out := [][]Cell{}
+	for i, phase := range phases {
+		end := NewCellText("")
+		duration := NewCellText("")
+		if !phase.end.IsZero() {
+			end = startupDurationCell(phase.end.Sub(created))
+			duration = startupDurationCell(phase.end.Sub(phase.start))
+		}
+
+		out = append(out, []Cell{
+			NewCellText(phase.phase),
+			NewCellText(phase.name),
+			startupDurationCell(phase.start.Sub(created)),
+			end,
+			duration,
+			NewCellText(gantt[i]),
+		})
+	}
+
+	return out, nil
+}
+
+// startupDurationCell shows the duration rounded to a tenth of a second, the number is stored in
+// milliseconds so it can be used with --match and --sort
+func startupDurationCell(d time.Duration) Cell {
+	return NewCellInt(d.Round(100*time.Millisecond).String(), d.Milliseconds())
+}
+
+// startupPhases builds the list of phases from the pod status and events in the order they
+// started, phases that have not started yet are not included
+func startupPhases(pod v1.Pod, events []v1.Event) []startupPhase {
+	created := pod.CreationTimestamp.Time
+	phases := []startupPhase{{phase: startupPhaseCreated, start: created, end: created}}
+
+	conditions := make(map[v1.PodConditionType]v1.PodCondition)
+	for _, condition := range pod.Status.Conditions {
+		conditions[condition.Type] = condition
+	}
+
+	scheduled := conditions[v1.PodScheduled]
+	if scheduled.Status == v1.ConditionTrue {
+		phases = append(phases, startupPhase{phase: startupPhaseScheduled, name: pod.Spec.NodeName, start: created, end: scheduled.LastTransitionTime.Time})
+	} else {
+		phases = append(phases, startupPhase{phase: startupPhaseScheduled, start: created})
+	}
+
+	phases = append(phases, startupPullPhases(events)...)
+
+	for _, container := range pod.Status.InitContainerStatuses {
+		phase := startupPhase{phase: startupPhaseInit, name: container.Name}
+		switch {
+		case container.State.Terminated != nil:
+			phase.start = container.State.Terminated.StartedAt.Time
+			phase.end = container.State.Terminated.FinishedAt.Time
+		case container.State.Running != nil:
+			// sidecars keep running so the phase ends once they have started
+			phase.start = container.State.Running.StartedAt.Time
+			if container.Started != nil && *container.Started {
+				phase.end = phase.start
+			}
+		default:
+			continue
+		}
+		phases = append(phases, phase)
+	}
+
+	// containers are created once the init containers have finished
+	initialized := conditions[v1.PodInitialized]
+	containersFrom := scheduled.LastTransitionTime.Time
+	if initialized.Status == v1.ConditionTrue {
+		containersFrom = initialized.LastTransitionTime.Time
+	}
+
+	var lastStarted time.Time
+	for _, container := range pod.Status.ContainerStatuses {
+		phase := startupPhase{phase: startupPhaseStart, name: container.Name, start: containersFrom}
+		switch {
+		case container.State.Running != nil:
+			phase.end = container.State.Running.StartedAt.Time
+		case container.State.Terminated != nil:
+			phase.end = container.State.Terminated.StartedAt.Time
+		}
+		if phase.start.IsZero() {
+			continue
+		}
+		if phase.end.After(lastStarted) {
+			lastStarted = phase.end
+		}
+		phases = append(phases, phase)
+	}
+
+	ready := conditions[v1.PodReady]
+	if !lastStarted.IsZero() {
+		phase := startupPhase{phase: startupPhaseReady, start: lastStarted}
+		if ready.Status == v1.ConditionTrue {
+			phase.end = ready.LastTransitionTime.Time
+		}
+		phases = append(phases, phase)
+	}
+
+	sort.SliceStable(phases, func(i, j int) bool {
+		return phases[i].start.Before(phases[j].start)
+	})
+
+	return phases
+}
+
+// startupPullPhases reads the image pull start and finish times from the Pulling and Pulled
+// events, the pull time in the Pulled message is used when available as event timestamps are
+// only accurate to the second
+func startupPullPhases(events []v1.Event) []startupPhase {
+	pulls := make(map[string]*startupPhase)
+	names := []string{}
+
+	for _, event := range events {
+		if event.Reason != "Pulling" && event.Reason != "Pulled" {
+			continue
+		}
+
+		containerName := eventContainerName(event)
+		if len(containerName) == 0 {
+			continue
+		}
+
+		phase, ok := pulls[containerName]
+		if !ok {
+			phase = &startupPhase{phase: startupPhasePull, name: containerName}
+			pulls[containerName] = phase
+			names = append(names, containerName)
+		}
+
+		// only the first pull is part of the startup, later pulls are from restarts
+		timestamp := eventFirstTime(event)
+		if event.Reason == "Pulling" {
+			if phase.start.IsZero() || timestamp.Before(phase.start) {
+				phase.start = timestamp
+			}
+			continue
+		}
+
+		if !phase.end.IsZero() && !timestamp.Before(phase.end) {
+			continue
+		}
+		phase.end = timestamp
+
+		// images already on the node are not pulled
+		if strings.Contains(event.Message, "already present") {
+			phase.start = timestamp
+			continue
+		}
+		if match := startupPulledRegexp.FindStringSubmatch(event.Message); match != nil {
+			if pullTime, err := time.ParseDuration(match[1]); err == nil {
+				phase.start = timestamp.Add(-pullTime)
+			}
+		}
+	}
+
+	phases := []startupPhase{}
+	for _, name := range names {
+		if pulls[name].start.IsZero() {
+			continue
+		}
+		phases = append(phases, *pulls[name])
+	}
+	return phases
+}
+
+// startupGantt draws a bar for each phase scaled so the pods whole startup fills width, phases
+// that are still running are drawn up to now
+func startupGantt(created time.Time, phases []startupPhase, now time.Time, width int) []string {
+	var total time.Duration
+	for _, phase := range phases {
+		end := phase.end
+		if end.IsZero() {
+			end = now
+		}
+		if end.Sub(created) > total {
+			total = end.Sub(created)
+		}
+	}
+	if total <= 0 {
+		total = 1
+	}
+
+	column := func(t time.Time) int {
+		col := int(float64(t.Sub(created)) / float64(total) * float64(width))
+		return max(0, min(width, col))
+	}
+
+	out := []string{}
+	for _, phase := range phases {
+		bar := "="
+		end := phase.end
+		if end.IsZero() {
+			bar = "-"
+			end = now
+		}
+
+		begin := column(phase.start)
+		length := max(1, column(end)-begin)
+		if begin+length > width {
+			begin = width - length
+		}
+
+		out = append(out, "|"+strings.Repeat(" ", begin)+strings.Repeat(bar, length)+strings.Repeat(" ", width-begin-length)+"|")
+	}
+	return out
+}
+
+// eventContainerName returns the container name from the events fieldPath, the fieldPath is in
+// the format spec.containers{name}
+func eventContainerName(event v1.Event) string {
+	fieldPath := event.InvolvedObject.FieldPath
+	start := strings.Index(fieldPath, "{")
+	end := strings.LastIndex(fieldPath, "}")
+	if start < 0 || end <= start {
+		return ""
+	}
+	return fieldPath[start+1 : end]
+}
+
+// eventFirstTime returns the time the event was first seen
+func eventFirstTime(event v1.Event) time.Time {
+	if !event.FirstTimestamp.IsZero() {
+		return event.FirstTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	if event.Series != nil {
+		return event.Series.LastObservedTime.Time
+	}
+	return event.LastTimestamp.Time
+}
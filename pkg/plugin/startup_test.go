@@ -0,0 +1,109 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var startupTestCreated = time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+func startupTestTime(seconds int) metav1.Time {
+	return metav1.NewTime(startupTestCreated.Add(time.Duration(seconds) * time.Second))
+}
+
+func startupTestEvent(reason string, container string, seconds int, message string) v1.Event {
+	return v1.Event{
+		Reason:         reason,
+		Message:        message,
+		FirstTimestamp: startupTestTime(seconds),
+		InvolvedObject: v1.ObjectReference{FieldPath: "spec.containers{" + container + "}"},
+	}
+}
+
+// *****************
+// startupPhases
+// *****************
+
+func TestStartupPhases(t *testing.T) {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(startupTestCreated)},
+		Spec:       v1.PodSpec{NodeName: "node1"},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: startupTestTime(1)},
+				{Type: v1.PodInitialized, Status: v1.ConditionTrue, LastTransitionTime: startupTestTime(20)},
+				{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: startupTestTime(90)},
+			},
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "migrate", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{StartedAt: startupTestTime(5), FinishedAt: startupTestTime(19)}}},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: startupTestTime(60)}}},
+			},
+		},
+	}
+
+	events := []v1.Event{
+		startupTestEvent("Pulling", "app", 20, `Pulling image "nginx"`),
+		startupTestEvent("Pulled", "app", 58, `Successfully pulled image "nginx" in 37.5s (37.5s including waiting). Image size: 1234 bytes.`),
+		startupTestEvent("Pulled", "sidecar", 30, `Container image "envoy" already present on machine`),
+		startupTestEvent("Started", "app", 60, `Started container app`),
+	}
+
+	expected := []struct {
+		phase string
+		name  string
+		start int
+		end   int
+	}{
+		{startupPhaseCreated, "", 0, 0},
+		{startupPhaseScheduled, "node1", 0, 1},
+		{startupPhaseInit, "migrate", 5, 19},
+		{startupPhaseStart, "app", 20, 60},
+		{startupPhasePull, "app", 20, 58}, // the pull time in the message is used, 58s-37.5s
+		{startupPhasePull, "sidecar", 30, 30},
+		{startupPhaseReady, "", 60, 90},
+	}
+
+	output := startupPhases(pod, events)
+	if len(output) != len(expected) {
+		t.Fatalf("Output has %d phases expected %d", len(output), len(expected))
+	}
+
+	for i, test := range expected {
+		phase := output[i]
+		start := int(phase.start.Sub(startupTestCreated).Seconds())
+		end := int(phase.end.Sub(startupTestCreated).Seconds())
+		if phase.phase != test.phase || phase.name != test.name || start != test.start || end != test.end {
+			t.Errorf("Output %d %s %s %d-%d not equal to expected %s %s %d-%d", i, phase.phase, phase.name, start, end, test.phase, test.name, test.start, test.end)
+		}
+	}
+}
+
+// *****************
+// startupGantt
+// *****************
+
+func TestStartupGantt(t *testing.T) {
+	phases := []startupPhase{
+		{phase: startupPhaseCreated, start: startupTestCreated, end: startupTestCreated},
+		{phase: startupPhaseInit, start: startupTestTime(0).Time, end: startupTestTime(5).Time},
+		{phase: startupPhaseReady, start: startupTestTime(5).Time},
+	}
+
+	expected := []string{
+		"|=         |",
+		"|=====     |",
+		"|     -----|",
+	}
+
+	output := startupGantt(startupTestCreated, phases, startupTestTime(10).Time, 10)
+	for i, line := range expected {
+		if output[i] != line {
+			t.Errorf("Output %q not equal to expected %q", output[i], line)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,7 +22,13 @@ are shown slong with current ready and running state. Pods and containers can al
 by name. If no name is specified the container state of all pods in the current namespace is
 shown.
 
-The T column in the table output denotes S for Standard and I for init containers`
+The --history flag shows the current state along side the last terminated state of each container,
+on tree views the LAST-REASON column counts the termination reasons of all containers below it.
+
+The QOS column shows the QoS class of the pod each container belongs to.
+
+The T column in the table output denotes S for Standard, I for init and K for sidecar containers,
+sidecar containers are counted toward the readiness of the pod`
 
 var statusExample = `  # List individual container status from pods
   %[1]s status
@@ -34,6 +42,9 @@ var statusExample = `  # List individual container status from pods
   # List previous container status from a single pod
   %[1]s status -p my-pod-4jh36
 
+  # List the current and last terminated state of containers in a single pod
+  %[1]s status --history my-pod-4jh36
+
   # List status of all containers named web-container searching all 
   # pods in the current namespace
   %[1]s status -c web-container
@@ -87,6 +98,13 @@ func Status(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args [
 		builder.ShowContainerType = true
 	}
 
+	if cmd.Flag("history").Value.String() == "true" {
+		if loopinfo.ShowPrevious {
+			return errors.New("--history can not be used with --previous as it already includes the previous state")
+		}
+		loopinfo.ShowHistory = true
+	}
+
 	if cmd.Flag("id").Value.String() == "true" {
 		log.Debug("loopinfo.ShowID = true")
 		loopinfo.ShowID = true
@@ -126,6 +144,7 @@ type status struct {
 	ShowPrevious bool
 	ShowDetails  bool
 	ShowID       bool // container id
+	ShowHistory  bool // show the last terminated state next to the current state
 
 	pNotReady     bool // Ready - we use the inverted term so the code makes more sense
 	pStopped      bool // Started - we use the inverted term so the code makes more sense
@@ -147,6 +166,11 @@ func (s *status) Headers() []string {
 		"TIMESTAMP",
 		"AGE",
 		"MESSAGE",
+		"LAST-STATE",
+		"LAST-REASON",
+		"LAST-EXIT",
+		"LAST-FINISHED",
+		"QOS",
 	}
 }
 
@@ -185,11 +209,16 @@ func (s *status) HideColumns(info BuilderInformation) []int {
 		}
 		hideColumns = tmpColumns
 	}
+
+	if !s.ShowHistory {
+		// hide LAST-STATE LAST-REASON LAST-EXIT LAST-FINISHED
+		hideColumns = append(hideColumns, 11, 12, 13, 14)
+	}
 	return hideColumns
 }
 
 func (s *status) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	rowOut := make([]Cell, 11)
+	rowOut := make([]Cell, len(s.Headers()))
 
 	// rowOut[0] // ready
 	// rowOut[1] // started
@@ -202,30 +231,64 @@ func (s *status) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, er
 	// rowOut[8] // timestamp
 	// rowOut[9] // age
 	// rowOut[10] // message
+	// rowOut[11] // last-state
+	// rowOut[12] // last-reason
+	// rowOut[13] // last-exit
+	// rowOut[14] // last-finished
+	// rowOut[15] // qos
 
 	rowOut[0].text = "true"
 	rowOut[0].colour = colourOk
 	rowOut[1].text = "true"
 	rowOut[1].colour = colourOk
 
+	lastReasons := make(map[string]int64)
+	qosCounts := make(map[string]int64)
+
 	// loop through each row in podTotals and add the columns in each row
 	for _, r := range rows {
-		if r[0].text == "false" {
-			// ready = false
-			rowOut[0].text = "false" // ready
-			rowOut[0].colour = colourBad
+		if info.TypeName == "Pod" {
+			// container rows hold a single reason
+			if len(r[12].text) > 0 {
+				lastReasons[r[12].text]++
+			}
+		} else {
+			for reason, count := range parseCountList(r[12].text) {
+				lastReasons[reason] += count
+			}
+			addCountList(qosCounts, r[15].text)
 		}
-		if r[1].text == "false" {
-			rowOut[1].text = "false" // started
-			rowOut[1].colour = colourBad
+
+		// pod readiness is read from the pod as the rows include init containers that have finished
+		if info.TypeName != "Pod" {
+			if r[0].text == "false" {
+				// ready = false
+				rowOut[0].text = "false" // ready
+				rowOut[0].colour = colourBad
+			}
+			if r[1].text == "false" {
+				rowOut[1].text = "false" // started
+				rowOut[1].colour = colourBad
+			}
 		}
 		rowOut[2].number += r[2].number // restarts
 
 	}
 
+	if info.TypeName == "Pod" {
+		ready, started := statusPodReady(info.Data.pod)
+		rowOut[0].text = fmt.Sprintf("%t", ready)
+		rowOut[0].colour = setColourBoolean(ready)
+		rowOut[1].text = fmt.Sprintf("%t", started)
+		rowOut[1].colour = setColourBoolean(started)
+	}
+
 	rowOut[2].typ = 1
 	rowOut[2].text = fmt.Sprintf("%d", rowOut[2].number)
 
+	rowOut[12].text = statusFormatReasonCounts(lastReasons)
+	rowOut[15].text = statusFormatReasonCounts(qosCounts)
+
 	switch info.TypeName {
 	case "Pod":
 		rawAge := time.Since(info.Data.pod.CreationTimestamp.Time)
@@ -239,11 +302,39 @@ func (s *status) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, er
 		rowOut[8].text = info.Data.pod.CreationTimestamp.Format(timestampFormat) // timestamp
 		rowOut[9].text = duration.HumanDuration(rawAge)                          // age
 		rowOut[10].text = info.Data.pod.Status.Message                           // message
+		rowOut[15] = qosCell(info.Data.pod)                                      // qos
 	}
 
 	return rowOut, nil
 }
 
+// statusPodReady returns if all containers in the pod are ready and started, sidecar containers
+// keep running so are counted along with the containers, other init containers are ignored
+func statusPodReady(pod v1.Pod) (bool, bool) {
+	ready := true
+	started := true
+
+	sidecars := sidecarContainerNames(pod)
+	statusList := []v1.ContainerStatus{}
+	for _, container := range pod.Status.InitContainerStatuses {
+		if sidecars[container.Name] {
+			statusList = append(statusList, container)
+		}
+	}
+	statusList = append(statusList, pod.Status.ContainerStatuses...)
+
+	for _, container := range statusList {
+		if !container.Ready {
+			ready = false
+		}
+		if container.Started != nil && !*container.Started {
+			started = false
+		}
+	}
+
+	return ready, started
+}
+
 func (s *status) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
 	var cellList []Cell
 	var reason string
@@ -341,6 +432,8 @@ func (s *status) BuildContainerStatus(container v1.ContainerStatus, info Builder
 
 	// container.ContainerID
 
+	lastCells := s.lastStateCells(container.LastTerminationState)
+
 	// READY STARTED RESTARTS STATE REASON EXIT-CODE SIGNAL TIMESTAMP AGE MESSAGE
 	cellList = append(cellList,
 		NewCellColourText(readyColour, ready),
@@ -355,6 +448,8 @@ func (s *status) BuildContainerStatus(container v1.ContainerStatus, info Builder
 		NewCellText(age),
 		NewCellText(message),
 	)
+	cellList = append(cellList, lastCells...)
+	cellList = append(cellList, qosCell(info.Data.pod))
 
 	log.Debug("len(cellList) =", len(cellList))
 
@@ -363,6 +458,42 @@ func (s *status) BuildContainerStatus(container v1.ContainerStatus, info Builder
 	return out, nil
 }
 
+// lastStateCells returns the LAST-STATE, LAST-REASON, LAST-EXIT and LAST-FINISHED columns
+func (s *status) lastStateCells(state v1.ContainerState) []Cell {
+	if state.Terminated == nil {
+		return []Cell{NewCellText(""), NewCellText(""), NewCellText(""), NewCellText("")}
+	}
+
+	exitCode := int64(state.Terminated.ExitCode)
+	return []Cell{
+		NewCellText("Terminated"),
+		NewCellText(state.Terminated.Reason),
+		NewCellColourInt(setColourBoolean(exitCode == 0), fmt.Sprintf("%d", exitCode), exitCode),
+		NewCellText(state.Terminated.FinishedAt.Format(timestampFormat)),
+	}
+}
+
+// statusFormatReasonCounts formats the termination reasons as reason:count, the most common
+// reason is listed first
+func statusFormatReasonCounts(counts map[string]int64) string {
+	reasons := []string{}
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] == counts[reasons[j]] {
+			return reasons[i] < reasons[j]
+		}
+		return counts[reasons[i]] > counts[reasons[j]]
+	})
+
+	list := []string{}
+	for _, reason := range reasons {
+		list = append(list, fmt.Sprintf("%s:%d", reason, counts[reason]))
+	}
+	return strings.Join(list, ",")
+}
+
 // Removes the pod name and container name from the status message as its already in the output table
 func (s *status) trimStatusMessage(message string, podName string, containerName string) string {
 
@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// *****************
+// BuildBranch
+// *****************
+
+func TestStatusBuildBranchLastReasons(t *testing.T) {
+	s := status{ShowHistory: true}
+
+	terminated := func(reason string, exitCode int32) v1.ContainerStatus {
+		return v1.ContainerStatus{
+			State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+				Reason:   reason,
+				ExitCode: exitCode,
+			}},
+		}
+	}
+
+	podRows := [][]Cell{}
+	for _, container := range []v1.ContainerStatus{terminated("OOMKilled", 137), terminated("Error", 1), {}} {
+		rows, _ := s.BuildContainerStatus(container, BuilderInformation{})
+		podRows = append(podRows, rows...)
+	}
+
+	if podRows[0][12].text != "OOMKilled" || podRows[0][13].text != "137" {
+		t.Errorf("Output last state %s %s not equal to expected OOMKilled 137", podRows[0][12].text, podRows[0][13].text)
+	}
+
+	pod, _ := s.BuildBranch(BuilderInformation{TypeName: "Pod"}, podRows)
+	if pod[12].text != "Error:1,OOMKilled:1" {
+		t.Errorf("Output pod reasons %s not equal to expected Error:1,OOMKilled:1", pod[12].text)
+	}
+
+	otherPod := make([]Cell, len(s.Headers()))
+	otherPod[12] = NewCellText("OOMKilled:2")
+
+	owner, _ := s.BuildBranch(BuilderInformation{TypeName: "ReplicaSet"}, [][]Cell{pod, otherPod})
+	if owner[12].text != "OOMKilled:3,Error:1" {
+		t.Errorf("Output owner reasons %s not equal to expected OOMKilled:3,Error:1", owner[12].text)
+	}
+}
+
+// *****************
+// statusPodReady
+// *****************
+
+func TestStatusPodReady(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	started := true
+	stopped := false
+
+	pod := v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "migrate"}, {Name: "proxy", RestartPolicy: &always}},
+		},
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "migrate", Ready: false, Started: &stopped},
+				{Name: "proxy", Ready: true, Started: &started},
+			},
+			ContainerStatuses: []v1.ContainerStatus{{Name: "app", Ready: true, Started: &started}},
+		},
+	}
+
+	// finished init containers are never ready so should not change the pod readiness
+	ready, isStarted := statusPodReady(pod)
+	if !ready || !isStarted {
+		t.Errorf("Output ready %t started %t not equal to expected true true", ready, isStarted)
+	}
+
+	pod.Status.InitContainerStatuses[1].Ready = false
+	ready, _ = statusPodReady(pod)
+	if ready {
+		t.Errorf("Output ready %t not equal to expected false when the sidecar is not ready", ready)
+	}
+
+	s := status{}
+	branch, _ := s.BuildBranch(BuilderInformation{TypeName: "Pod", Data: ParentData{pod: pod}}, [][]Cell{})
+	if branch[0].text != "false" {
+		t.Errorf("Output pod ready %s not equal to expected false", branch[0].text)
+	}
+}
@@ -719,6 +719,11 @@ func (t *Table) ListOutOfRange(columnID int) ([]int, error) {
 	var upperFenceInt, lowerFenceInt int64
 	var upperFenceFloat, lowerFenceFloat float64
 
+	// every row may have been filtered out so there is nothing to check
+	if len(t.data) == 0 {
+		return []int{}, nil
+	}
+
 	cellType := t.data[0][columnID].typ
 
 	if cellType == 0 {
@@ -0,0 +1,251 @@
+package plugin
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var terminationsShort = "List containers that were OOMKilled or terminated with an error"
+
+var terminationsDescription = ` Prints a row for each container whose current or last state is terminated with the reason
+OOMKilled, Error or ContainerStatusUnknown. The STATE column shows if the termination is the
+current state of the container or the last state before it was restarted.
+
+The termination message is shown in full, unlike the status command the message is not trimmed,
+line breaks are shown as \n so each termination stays on a single row. The message policy and path
+are read from the container spec.
+
+MEM-LIMIT is the memory limit currently applied to the container, this is the limit the container
+was running with unless the container has been resized since. When connected to a cluster the
+current memory usage is read from the metrics server if available.`
+
+var terminationsExample = `  # List terminated containers of all pods in the current namespace
+  %[1]s terminations
+
+  # List only the containers that were OOMKilled
+  %[1]s oom --match REASON==OOMKilled
+
+  # List terminated containers from a single pod output in JSON format
+  %[1]s terminations my-pod-4jh36 -o json
+
+  # List terminated containers from all pods where label app equals web
+  %[1]s terminations -l app=web`
+
+// termination reasons that are listed
+var terminationsReasons = []string{"OOMKilled", "Error", "ContainerStatusUnknown"}
+
+func Terminations(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Terminations"}
+	log.Debug("Start")
+
+	loopinfo := terminations{}
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.ShowInitContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+
+	// only need to pull metrics info we are reading live data,
+	// if we read from a file metric data wont exist
+	if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
+		if err := connect.LoadMetricConfig(kubeFlags); err != nil {
+			return err
+		}
+		podStateList, err := connect.GetMetricPods(args)
+		if err != nil {
+			log.Tell(err)
+		} else {
+			loopinfo.MetricsResource = podMetrics2Hashtable(podStateList)
+		}
+	}
+
+	if cmd.Flag("size") != nil {
+		if len(cmd.Flag("size").Value.String()) > 0 {
+			loopinfo.BytesAs = cmd.Flag("size").Value.String()
+		}
+	}
+
+	table := Table{}
+	table.ColourOutput = commonFlagList.outputAsColour
+	table.CustomColours = commonFlagList.useTheseColours
+
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type terminations struct {
+	MetricsResource map[string]map[string]v1.ResourceList
+	BytesAs         string
+}
+
+func (s *terminations) Headers() []string {
+	return []string{
+		"STATE", "REASON", "EXIT-CODE", "SIGNAL", "FINISHED", "MEM-LIMIT", "MEM-USED", "%LIMIT", "MESSAGE-POLICY", "MESSAGE-PATH", "MESSAGE",
+	}
+}
+
+func (s *terminations) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *terminations) BuildEphemeralContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *terminations) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+// BuildBranch counts the terminations for each reason
+func (s *terminations) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, len(s.Headers()))
+	for i := range rowOut {
+		rowOut[i] = NewCellText("")
+	}
+
+	reasons := make(map[string]int64)
+	for _, r := range rows {
+		if info.TypeName == TypeNamePod {
+			reasons[r[1].text]++
+			continue
+		}
+		for reason, count := range parseCountList(r[1].text) {
+			reasons[reason] += count
+		}
+	}
+
+	rowOut[1] = NewCellText(statusFormatReasonCounts(reasons))
+	return rowOut, nil
+}
+
+func (s *terminations) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return s.terminationsBuildRows(container, info), nil
+}
+
+func (s *terminations) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return s.terminationsBuildRows(v1.Container(container.EphemeralContainerCommon), info), nil
+}
+
+func (s *terminations) terminationsBuildRows(container v1.Container, info BuilderInformation) [][]Cell {
+	out := [][]Cell{}
+
+	status := containerStatus(info)
+	if status == nil {
+		return out
+	}
+
+	// use the resources applied to the running container when the kubelet reports them
+	limit := container.Resources.Limits.Memory()
+	if status.Resources != nil && status.Resources.Limits != nil {
+		limit = status.Resources.Limits.Memory()
+	}
+
+	policy := string(container.TerminationMessagePolicy)
+	if len(policy) == 0 {
+		policy = string(v1.TerminationMessageReadFile)
+	}
+	path := container.TerminationMessagePath
+	if len(path) == 0 {
+		path = v1.TerminationMessagePathDefault
+	}
+
+	states := []struct {
+		name  string
+		state *v1.ContainerStateTerminated
+	}{
+		{"current", status.State.Terminated},
+		{"last", status.LastTerminationState.Terminated},
+	}
+
+	for _, state := range states {
+		if state.state == nil || !slices.Contains(terminationsReasons, state.state.Reason) {
+			continue
+		}
+
+		row := []Cell{
+			NewCellText(state.name),
+			NewCellColourText(colourBad, state.state.Reason),
+			NewCellInt(fmt.Sprintf("%d", state.state.ExitCode), int64(state.state.ExitCode)),
+			NewCellInt(fmt.Sprintf("%d", state.state.Signal), int64(state.state.Signal)),
+			NewCellText(state.state.FinishedAt.Format(timestampFormat)),
+		}
+		row = append(row, s.memoryCells(limit.Value(), info)...)
+		row = append(row,
+			NewCellText(policy),
+			NewCellText(path),
+			NewCellText(terminationsMessage(state.state.Message)),
+		)
+		out = append(out, row)
+	}
+
+	return out
+}
+
+// memoryCells returns the MEM-LIMIT, MEM-USED and %LIMIT columns
+func (s *terminations) memoryCells(limit int64, info BuilderInformation) []Cell {
+	limitCell := NewCellText("")
+	if limit > 0 {
+		limitCell = NewCellInt(memoryHumanReadable(limit, s.BytesAs), limit)
+	}
+
+	metrics, ok := s.MetricsResource[info.PodName][info.Name]
+	if !ok || metrics.Memory() == nil {
+		return []Cell{limitCell, NewCellText(""), NewCellText("")}
+	}
+
+	used := metrics.Memory().Value()
+	percent := NewCellText("")
+	if limit > 0 {
+		val := validateFloat64(float64(used) / float64(limit) * 100)
+		percent = NewCellColourFloat(setColourValue(int(val)), fmt.Sprintf("%.2f", val), val)
+	}
+
+	return []Cell{limitCell, NewCellInt(memoryHumanReadable(used, s.BytesAs), used), percent}
+}
+
+// terminationsMessage keeps the full message but shows line breaks as \n so the message fits on
+// a single row
+func terminationsMessage(message string) string {
+	message = strings.TrimRight(message, "\r\n")
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	return strings.ReplaceAll(message, "\n", `\n`)
+}
+
+func (s *terminations) BuildPodRow(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// *****************
+// terminationsBuildRows
+// *****************
+
+func TestTerminationsBuildRows(t *testing.T) {
+	s := terminations{
+		BytesAs: "Mi",
+		MetricsResource: map[string]map[string]v1.ResourceList{
+			"web-1": {"app": {v1.ResourceMemory: apires.MustParse("96Mi")}},
+		},
+	}
+
+	container := v1.Container{
+		Name: "app",
+		Resources: v1.ResourceRequirements{Limits: v1.ResourceList{
+			v1.ResourceMemory: apires.MustParse("128Mi"),
+		}},
+	}
+
+	info := BuilderInformation{PodName: "web-1", Name: "app", ContainerType: TypeIDContainer}
+	info.Data.pod.Status.ContainerStatuses = []v1.ContainerStatus{{
+		Name:                 "app",
+		State:                v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, Message: "panic: boom\n"}},
+		LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137, Message: "line1\nline2"}},
+	}}
+
+	output := s.terminationsBuildRows(container, info)
+	if len(output) != 2 {
+		t.Fatalf("Output has %d rows expected 2", len(output))
+	}
+
+	expected := [][]string{
+		{"current", "Error", "1", "128.00Mi", "96.00Mi", "75.00", "File", "/dev/termination-log", "panic: boom"},
+		{"last", "OOMKilled", "137", "128.00Mi", "96.00Mi", "75.00", "File", "/dev/termination-log", `line1\nline2`},
+	}
+	columns := []int{0, 1, 2, 5, 6, 7, 8, 9, 10}
+	for i, row := range expected {
+		for j, col := range columns {
+			if output[i][col].text != row[j] {
+				t.Errorf("Output row %d column %d %s not equal to expected %s", i, col, output[i][col].text, row[j])
+			}
+		}
+	}
+
+	// completed containers are not listed
+	info.Data.pod.Status.ContainerStatuses[0].State.Terminated.Reason = "Completed"
+	info.Data.pod.Status.ContainerStatuses[0].LastTerminationState = v1.ContainerState{}
+	if output := s.terminationsBuildRows(container, info); len(output) != 0 {
+		t.Errorf("Output has %d rows expected 0 for a completed container", len(output))
+	}
+}
+
+// *****************
+// BuildBranch
+// *****************
+
+func TestTerminationsBuildBranch(t *testing.T) {
+	s := terminations{}
+
+	row := func(reason string) []Cell {
+		cells := make([]Cell, len(s.Headers()))
+		cells[1] = NewCellText(reason)
+		return cells
+	}
+
+	pod, _ := s.BuildBranch(BuilderInformation{TypeName: TypeNamePod}, [][]Cell{row("OOMKilled"), row("Error"), row("OOMKilled")})
+	if pod[1].text != "OOMKilled:2,Error:1" {
+		t.Errorf("Output pod reasons %s not equal to expected OOMKilled:2,Error:1", pod[1].text)
+	}
+
+	owner, _ := s.BuildBranch(BuilderInformation{TypeName: "ReplicaSet"}, [][]Cell{pod, row("Error:2")})
+	if owner[1].text != "Error:3,OOMKilled:2" {
+		t.Errorf("Output owner reasons %s not equal to expected Error:3,OOMKilled:2", owner[1].text)
+	}
+}
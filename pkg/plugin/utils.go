@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -250,3 +251,173 @@ func getColourSetFromString(colours []string) ([][2]int, int, error) {
 	}
 	return colourArray, colourset, nil
 }
+
+// parseCountList reads a list of name:count separated by commas as created by the tree branches
+// and returns the count for each name
+func parseCountList(summary string) map[string]int64 {
+	counts := make(map[string]int64)
+	if len(summary) == 0 {
+		return counts
+	}
+
+	for _, item := range strings.Split(summary, ",") {
+		idx := strings.LastIndex(item, ":")
+		if idx < 0 {
+			continue
+		}
+		count, err := strconv.ParseInt(item[idx+1:], 10, 64)
+		if err != nil {
+			continue
+		}
+		counts[item[:idx]] += count
+	}
+
+	return counts
+}
+
+// containerStatus returns the status of the container currently being processed, containers
+// are matched on name as the runtime is free to report a normalised image name
+func containerStatus(info BuilderInformation) *v1.ContainerStatus {
+	var statusList []v1.ContainerStatus
+
+	switch info.ContainerType {
+	case TypeIDInitContainer, TypeIDSidecarContainer:
+		statusList = info.Data.pod.Status.InitContainerStatuses
+	case TypeIDEphemeralContainer:
+		statusList = info.Data.pod.Status.EphemeralContainerStatuses
+	default:
+		statusList = info.Data.pod.Status.ContainerStatuses
+	}
+
+	for i, status := range statusList {
+		if status.Name == info.Name {
+			return &statusList[i]
+		}
+	}
+
+	return nil
+}
+
+// isSidecarContainer returns true for init containers that keep running alongside the containers,
+// these are init containers with a restartPolicy of Always
+func isSidecarContainer(container v1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways
+}
+
+// sidecarContainerNames returns the names of all sidecar containers in the pod spec
+func sidecarContainerNames(pod v1.Pod) map[string]bool {
+	names := make(map[string]bool)
+	for _, container := range pod.Spec.InitContainers {
+		if isSidecarContainer(container) {
+			names[container.Name] = true
+		}
+	}
+	return names
+}
+
+// addCountList adds the name:count list created by a tree branch to counts, rows that hold a
+// single name without a count are counted once
+func addCountList(counts map[string]int64, summary string) {
+	parsed := parseCountList(summary)
+	if len(parsed) == 0 && len(summary) > 0 {
+		parsed[summary] = 1
+	}
+
+	for name, count := range parsed {
+		counts[name] += count
+	}
+}
+
+// podQOSClass returns the QoS class of the pod, when the class is missing from the pod status it
+// is calculated from the cpu and memory resources the same way the kubelet does
+func podQOSClass(pod v1.Pod) v1.PodQOSClass {
+	if len(pod.Status.QOSClass) > 0 {
+		return pod.Status.QOSClass
+	}
+
+	resourceList := []v1.ResourceRequirements{}
+	if pod.Spec.Resources != nil {
+		// pod level resources replace the container resources
+		resourceList = append(resourceList, *pod.Spec.Resources)
+	} else {
+		for _, container := range pod.Spec.InitContainers {
+			resourceList = append(resourceList, container.Resources)
+		}
+		for _, container := range pod.Spec.Containers {
+			resourceList = append(resourceList, container.Resources)
+		}
+	}
+
+	requests := v1.ResourceList{}
+	limits := v1.ResourceList{}
+	isGuaranteed := true
+	for _, res := range resourceList {
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			request, hasRequest := res.Requests[name]
+			limit, hasLimit := res.Limits[name]
+			if hasLimit && !limit.IsZero() {
+				limits[name] = limit
+				// requests default to the limit when they are not set
+				if !hasRequest {
+					request, hasRequest = limit, true
+				}
+			} else {
+				isGuaranteed = false
+			}
+			if hasRequest && !request.IsZero() {
+				requests[name] = request
+				if !hasLimit || request.Cmp(limit) != 0 {
+					isGuaranteed = false
+				}
+			}
+		}
+	}
+
+	if len(requests) == 0 && len(limits) == 0 {
+		return v1.PodQOSBestEffort
+	}
+
+	if isGuaranteed {
+		return v1.PodQOSGuaranteed
+	}
+
+	return v1.PodQOSBurstable
+}
+
+// qosColour returns the colour used to show the QoS class, best effort pods are the first to be
+// evicted so are shown as a warning
+func qosColour(class v1.PodQOSClass) [2]int {
+	switch class {
+	case v1.PodQOSGuaranteed:
+		return colourOk
+	case v1.PodQOSBestEffort:
+		return colourWarn
+	}
+	return [2]int{-1, 0}
+}
+
+// qosCell returns the QoS class of the pod as a cell
+func qosCell(pod v1.Pod) Cell {
+	qos := podQOSClass(pod)
+	return NewCellColourText(qosColour(qos), string(qos))
+}
+
+// ownerPodTemplate returns the pod template of a workload, false is returned when the owner does not
+// have a pod template
+func ownerPodTemplate(owner ParentData) (v1.PodSpec, bool) {
+	switch owner.kind {
+	case TypeNameDeployment:
+		return owner.deployment.Spec.Template.Spec, true
+	case TypeNameReplicaSet:
+		return owner.replica.Spec.Template.Spec, true
+	case TypeNameStatefulSet:
+		return owner.stateful.Spec.Template.Spec, true
+	case TypeNameDaemonSet:
+		return owner.daemon.Spec.Template.Spec, true
+	case TypeNameJob:
+		return owner.job.Spec.Template.Spec, true
+	case TypeNameCronJob:
+		return owner.cronjob.Spec.JobTemplate.Spec.Template.Spec, true
+	}
+	return v1.PodSpec{}, false
+}
@@ -4,6 +4,8 @@ import (
 	"math"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -170,3 +172,47 @@ func TestDemo(t *testing.T) {
 		}
 	}
 }
+
+// *******************
+// podQOSClass
+// *******************
+func TestPodQOSClass(t *testing.T) {
+	resources := func(request string, limit string) v1.ResourceRequirements {
+		res := v1.ResourceRequirements{Requests: v1.ResourceList{}, Limits: v1.ResourceList{}}
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			if len(request) > 0 {
+				res.Requests[name] = apires.MustParse(request)
+			}
+			if len(limit) > 0 {
+				res.Limits[name] = apires.MustParse(limit)
+			}
+		}
+		return res
+	}
+
+	tests := []struct {
+		name     string
+		pod      v1.Pod
+		expected v1.PodQOSClass
+	}{
+		{"status", v1.Pod{Status: v1.PodStatus{QOSClass: v1.PodQOSGuaranteed}}, v1.PodQOSGuaranteed},
+		{"no resources", v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{}}}}, v1.PodQOSBestEffort},
+		{"requests equal limits", v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Resources: resources("1", "1")}}}}, v1.PodQOSGuaranteed},
+		{"limits only", v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Resources: resources("", "1")}}}}, v1.PodQOSGuaranteed},
+		{"requests below limits", v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Resources: resources("1", "2")}}}}, v1.PodQOSBurstable},
+		{"one container without limits", v1.Pod{Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{}},
+			Containers:     []v1.Container{{Resources: resources("1", "1")}},
+		}}, v1.PodQOSBurstable},
+		{"pod level resources", v1.Pod{Spec: v1.PodSpec{
+			Resources:  &v1.ResourceRequirements{Limits: resources("", "1").Limits},
+			Containers: []v1.Container{{}},
+		}}, v1.PodQOSGuaranteed},
+	}
+
+	for _, test := range tests {
+		if output := podQOSClass(test.pod); output != test.expected {
+			t.Errorf("%s: output %s not equal to expected %s", test.name, output, test.expected)
+		}
+	}
+}
@@ -2,11 +2,12 @@ package plugin
 
 import (
 	"fmt"
-	"os"
 	"reflect"
+	"strings"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -15,7 +16,16 @@ var volumesShort = "Display container volumes and mount points"
 var volumesDescription = ` Prints configured volume information at the container level, volume type, backing information,
 read-write state and mount point are all avaliable, volume size is only available if found in
 the pod configuration. If no name is specified the volume information for all pods in the
-current namespace are shown.`
+current namespace are shown.
+
+Using the --claims flag persistent volume claims are followed to their bound persistent volume
+and storage class showing the requested and actual capacity, access modes, reclaim policy and
+provisioner details.
+
+The --detail flag shows how each volume is mounted, subPath and subPathExpr mounts (which stop
+ConfigMap and Secret updates reaching the container), mount propagation, recursive read only,
+file modes and the keys that are projected into the mount. Keys that are missing from the live
+ConfigMap or Secret are also listed, unknown is shown when the ConfigMap or Secret could not be read.`
 
 var volumesExample = `  # List volumes from containers inside pods from current namespace
   %[1]s volumes
@@ -42,7 +52,16 @@ var volumesExample = `  # List volumes from containers inside pods from current
   %[1]s volumes -l app=web
 
   # List volumes from all containers where the pod label app is web or mail
-  %[1]s volumes -l "app in (web,mail)"`
+  %[1]s volumes -l "app in (web,mail)"
+
+  # List volumes along with the details of the bound claims, persistent volumes and storage classes
+  %[1]s volumes --claims
+
+  # List the claims used by the pods of each statefulset in a tree view
+  %[1]s volumes --claims --tree
+
+  # List mount details and any configmap or secret keys that are missing
+  %[1]s volumes --detail`
 
 func Volumes(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
 
@@ -72,6 +91,27 @@ func Volumes(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 		loopinfo.ShowVolumeDevice = true
 	}
 
+	if cmd.Flag("claims").Value.String() == "true" {
+		log.Debug("loopinfo.ShowClaims = true")
+		loopinfo.ShowClaims = true
+	}
+
+	if cmd.Flag("detail").Value.String() == "true" {
+		log.Debug("loopinfo.ShowDetail = true")
+		loopinfo.ShowDetail = true
+	}
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+
+	// we need the connection details so we can lookup claims, volumes, storage classes and check the
+	// configmap and secret keys exist, this is only possible when we are reading live data
+	if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
+		loopinfo.Connection = &connect
+	}
+
 	table := Table{}
 	table.ColourOutput = commonFlagList.outputAsColour
 	table.CustomColours = commonFlagList.useTheseColours
@@ -93,7 +133,18 @@ func Volumes(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 }
 
 type volumes struct {
+	Connection       *Connector
 	ShowVolumeDevice bool
+	ShowClaims       bool
+	ShowDetail       bool
+}
+
+// volumeKeySource holds the keys of a ConfigMap or Secret that are projected into a volume
+type volumeKeySource struct {
+	kind     string
+	name     string
+	optional bool
+	items    []v1.KeyToPath
 }
 
 func (s *volumes) Headers() []string {
@@ -105,6 +156,24 @@ func (s *volumes) Headers() []string {
 			"SIZE",
 			"RO",
 			"MOUNT-POINT",
+			"PV",
+			"REQUEST",
+			"CAPACITY",
+			"ACCESS",
+			"VOLUME-MODE",
+			"PHASE",
+			"RECLAIM",
+			"CLASS",
+			"PROVISIONER",
+			"CSI-DRIVER",
+			"VOLUME-HANDLE",
+			"EXPANDABLE",
+			"SUB-PATH",
+			"PROPAGATION",
+			"RECURSIVE-RO",
+			"MODE",
+			"ITEMS",
+			"MISSING-KEYS",
 		}
 	} else {
 		return []string{
@@ -123,25 +192,49 @@ func (s *volumes) BuildEphemeralContainerStatus(container v1.ContainerStatus, in
 }
 
 func (s *volumes) HideColumns(info BuilderInformation) []int {
-	return []int{}
+	var hideColumns []int
+
+	if s.ShowVolumeDevice {
+		return hideColumns
+	}
+
+	if !s.ShowClaims {
+		// hide the claim columns
+		hideColumns = append(hideColumns, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17)
+	}
+
+	if !s.ShowDetail {
+		// hide the mount detail columns
+		hideColumns = append(hideColumns, 18, 19, 20, 21, 22, 23)
+	}
+
+	return hideColumns
 }
 
 func (s *volumes) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	var out []Cell
-
-	if !s.ShowVolumeDevice {
-		out = []Cell{
-			NewCellText(""),
-			NewCellText(""),
-			NewCellText(""),
-			NewCellText(""),
-			NewCellText(""),
-			NewCellText(""),
+	out := make([]Cell, len(s.Headers()))
+
+	if s.ShowClaims && !s.ShowVolumeDevice && info.TypeName == TypeNameStatefulSet {
+		// show the claim templates that the per-ordinal claims in each pod are created from
+		templateNames := []string{}
+		requestList := []string{}
+		accessList := []string{}
+		classList := []string{}
+		for _, template := range info.Data.stateful.Spec.VolumeClaimTemplates {
+			templateNames = append(templateNames, template.Name)
+			requestList = append(requestList, template.Spec.Resources.Requests.Storage().String())
+			accessList = append(accessList, accessModesAsString(template.Spec.AccessModes))
+			if template.Spec.StorageClassName != nil {
+				classList = append(classList, *template.Spec.StorageClassName)
+			}
 		}
-	} else {
-		out = []Cell{
-			NewCellText(""),
-			NewCellText(""),
+
+		if len(templateNames) > 0 {
+			out[0] = NewCellText(strings.Join(templateNames, ","))
+			out[1] = NewCellText("VolumeClaimTemplate")
+			out[7] = NewCellText(strings.Join(requestList, ","))
+			out[9] = NewCellText(strings.Join(accessList, ","))
+			out[13] = NewCellText(strings.Join(classList, ","))
 		}
 	}
 
@@ -153,6 +246,7 @@ func (s *volumes) BuildContainerSpec(container v1.Container, info BuilderInforma
 	Pod := info.Data.pod
 	if !s.ShowVolumeDevice {
 		podVolumes := s.createVolumeMap(Pod.Spec.Volumes)
+		s.addClaimTemplates(Pod, podVolumes)
 		for _, mount := range container.VolumeMounts {
 			out = append(out, s.volumesBuildRow(info, podVolumes, mount))
 		}
@@ -168,6 +262,7 @@ func (s *volumes) BuildEphemeralContainerSpec(container v1.EphemeralContainer, i
 	out := [][]Cell{}
 	if !s.ShowVolumeDevice {
 		podVolumes := s.createVolumeMap(info.Data.pod.Spec.Volumes)
+		s.addClaimTemplates(info.Data.pod, podVolumes)
 		for _, mount := range container.VolumeMounts {
 			out = append(out, s.volumesBuildRow(info, podVolumes, mount))
 		}
@@ -218,12 +313,28 @@ func (s *volumes) decodeVolumeType(volType string, volume v1.VolumeSource) map[s
 	case "AzureFile":
 		outMap["backing"] = NewCellText(volume.AzureFile.ShareName)
 
+	case "CephFS":
+		path := volume.CephFS.Path
+		if len(path) == 0 {
+			path = "/"
+		}
+		outMap["backing"] = NewCellText(strings.Join(volume.CephFS.Monitors, ",") + ":" + path)
+
 	case "Cinder":
 		outMap["backing"] = NewCellText(volume.Cinder.VolumeID)
 
 	case "ConfigMap":
 		outMap["backing"] = NewCellText(volume.ConfigMap.Name)
 
+	case "CSI":
+		outMap["backing"] = NewCellText(volume.CSI.Driver)
+		// there is no standard size field for inline volumes but most drivers accept a size attribute
+		if size, ok := volume.CSI.VolumeAttributes["size"]; ok {
+			if quantity, err := apires.ParseQuantity(size); err == nil {
+				outMap["size"] = NewCellInt(quantity.String(), quantity.Value())
+			}
+		}
+
 	case "DownwardAPI":
 		str := ""
 		sep := ""
@@ -242,20 +353,57 @@ func (s *volumes) decodeVolumeType(volType string, volume v1.VolumeSource) map[s
 		outMap["backing"] = NewCellText(string(volume.EmptyDir.Medium))
 
 	case "Ephemeral":
-		outMap["backing"] = NewCellText(volume.Ephemeral.VolumeClaimTemplate.Name)
+		if template := volume.Ephemeral.VolumeClaimTemplate; template != nil {
+			if template.Spec.StorageClassName != nil {
+				outMap["backing"] = NewCellText(*template.Spec.StorageClassName)
+			} else {
+				outMap["backing"] = NewCellText(template.Name)
+			}
+			if size := template.Spec.Resources.Requests.Storage(); !size.IsZero() {
+				outMap["size"] = NewCellInt(size.String(), size.Value())
+			}
+		}
 
 	case "FC":
-		outMap["backing"] = NewCellText(volume.FC.TargetWWNs[0])
+		if len(volume.FC.TargetWWNs) > 0 {
+			lun := ""
+			if volume.FC.Lun != nil {
+				lun = fmt.Sprintf(":%d", *volume.FC.Lun)
+			}
+			outMap["backing"] = NewCellText(volume.FC.TargetWWNs[0] + lun)
+		} else if len(volume.FC.WWIDs) > 0 {
+			outMap["backing"] = NewCellText(volume.FC.WWIDs[0])
+		}
+
+	case "FlexVolume":
+		outMap["backing"] = NewCellText(volume.FlexVolume.Driver)
 
 	case "Flocker":
-		outMap["backing"] = NewCellText(volume.Flocker.DatasetUUID)
+		if len(volume.Flocker.DatasetName) > 0 {
+			outMap["backing"] = NewCellText(volume.Flocker.DatasetName)
+		} else {
+			outMap["backing"] = NewCellText(volume.Flocker.DatasetUUID)
+		}
 
 	case "GCEPersistentDisk":
 		outMap["backing"] = NewCellText(volume.GCEPersistentDisk.PDName)
 
+	case "GitRepo":
+		backing := volume.GitRepo.Repository
+		if len(volume.GitRepo.Revision) > 0 {
+			backing += "@" + volume.GitRepo.Revision
+		}
+		outMap["backing"] = NewCellText(backing)
+
+	case "Glusterfs":
+		outMap["backing"] = NewCellText(volume.Glusterfs.EndpointsName + ":" + volume.Glusterfs.Path)
+
 	case "HostPath":
 		outMap["backing"] = NewCellText(volume.HostPath.Path)
 
+	case "Image":
+		outMap["backing"] = NewCellText(volume.Image.Reference)
+
 	case "ISCSI":
 		outMap["backing"] = NewCellText(volume.ISCSI.IQN)
 
@@ -264,6 +412,7 @@ func (s *volumes) decodeVolumeType(volType string, volume v1.VolumeSource) map[s
 
 	case "PersistentVolumeClaim":
 		outMap["backing"] = NewCellText(volume.PersistentVolumeClaim.ClaimName)
+		outMap["claim"] = NewCellText(volume.PersistentVolumeClaim.ClaimName)
 
 	case "PhotonPersistentDisk":
 		outMap["backing"] = NewCellText(volume.PhotonPersistentDisk.PdID)
@@ -272,17 +421,32 @@ func (s *volumes) decodeVolumeType(volType string, volume v1.VolumeSource) map[s
 		outMap["backing"] = NewCellText(volume.PortworxVolume.VolumeID)
 
 	case "Projected":
-		tmp := ""
-		// TODO: needs reworking it looks fuggly
+		sourceList := []string{}
 		for _, val := range volume.Projected.Sources {
 			if val.ConfigMap != nil {
-				tmp += val.ConfigMap.Name + ","
+				sourceList = append(sourceList, val.ConfigMap.Name)
+			}
+			if val.Secret != nil {
+				sourceList = append(sourceList, val.Secret.Name)
+			}
+			if val.DownwardAPI != nil {
+				sourceList = append(sourceList, "downwardAPI")
+			}
+			if val.ServiceAccountToken != nil {
+				sourceList = append(sourceList, "serviceAccountToken")
+			}
+			if val.ClusterTrustBundle != nil {
+				if val.ClusterTrustBundle.Name != nil {
+					sourceList = append(sourceList, *val.ClusterTrustBundle.Name)
+				} else {
+					sourceList = append(sourceList, "clusterTrustBundle")
+				}
+			}
+			if val.PodCertificate != nil {
+				sourceList = append(sourceList, "podCertificate")
 			}
 		}
-		if len(tmp) > 0 {
-			tmp = tmp[:len(tmp)-1]
-		}
-		outMap["backing"] = NewCellText(tmp)
+		outMap["backing"] = NewCellText(strings.Join(sourceList, ","))
 
 	case "Quobyte":
 		outMap["backing"] = NewCellText(volume.Quobyte.Tenant)
@@ -290,6 +454,9 @@ func (s *volumes) decodeVolumeType(volType string, volume v1.VolumeSource) map[s
 	case "RBD":
 		outMap["backing"] = NewCellText(volume.RBD.RBDImage)
 
+	case "ScaleIO":
+		outMap["backing"] = NewCellText(volume.ScaleIO.System + "/" + volume.ScaleIO.VolumeName)
+
 	case "Secret":
 		outMap["backing"] = NewCellText(volume.Secret.SecretName)
 
@@ -300,8 +467,10 @@ func (s *volumes) decodeVolumeType(volType string, volume v1.VolumeSource) map[s
 		outMap["backing"] = NewCellText(volume.VsphereVolume.VolumePath)
 
 	default:
-		fmt.Fprintln(os.Stderr, "ERROR: unknown volume type", volType)
-		return nil
+		// volume types added after this was written are still shown, we just cant decode them
+		log := logger{location: "volumes:decodeVolumeType"}
+		log.Debug("unknown volume type", volType)
+		outMap["type"] = NewCellText("<" + volType + ">")
 	}
 	return outMap
 }
@@ -311,12 +480,28 @@ func (s *volumes) volumesBuildRow(info BuilderInformation, podVolumes map[string
 	var volumeType Cell
 	var size Cell
 	var backing Cell
+	var claimName string
 
 	if podVolumes[mount.Name] != nil {
 		volume := podVolumes[mount.Name]
 		volumeType = volume["type"]
 		size = volume["size"]
 		backing = volume["backing"]
+		claimName = volume["claim"].text
+
+		// generic ephemeral volumes create a claim named after the pod and volume
+		if volumeType.text == "Ephemeral" {
+			claimName = info.PodName + "-" + mount.Name
+		}
+	}
+
+	claimCells := make([]Cell, 12)
+	if s.ShowClaims && s.Connection != nil && len(claimName) > 0 {
+		claimCells = s.claimBuildCells(info.Data.pod.Namespace, claimName)
+		// the actual capacity is the closest we can get to the volume size
+		if claimCells[2].number > 0 {
+			size = claimCells[2]
+		}
 	}
 
 	cellList = append(cellList,
@@ -327,9 +512,338 @@ func (s *volumes) volumesBuildRow(info BuilderInformation, podVolumes map[string
 		NewCellText(fmt.Sprintf("%t", mount.ReadOnly)),
 		NewCellText(mount.MountPath))
 
+	cellList = append(cellList, claimCells...)
+
+	detailCells := make([]Cell, 6)
+	if s.ShowDetail {
+		detailCells = s.detailBuildCells(info, volumeType.text, mount)
+	}
+	cellList = append(cellList, detailCells...)
+
 	return cellList
 }
 
+// detailBuildCells returns the mount detail cells "SUB-PATH","PROPAGATION","RECURSIVE-RO","MODE","ITEMS","MISSING-KEYS"
+func (s *volumes) detailBuildCells(info BuilderInformation, volumeType string, mount v1.VolumeMount) []Cell {
+	var subPath, propagation, recursiveRO, mode string
+	var keySources []volumeKeySource
+	var defaultMode *int32
+
+	subPathColour := [2]int{-1, 0}
+	propagationColour := [2]int{-1, 0}
+	missingColour := [2]int{-1, 0}
+
+	if len(mount.SubPath) > 0 {
+		subPath = mount.SubPath
+	}
+	if len(mount.SubPathExpr) > 0 {
+		subPath = "expr:" + mount.SubPathExpr
+	}
+
+	if len(subPath) > 0 {
+		switch volumeType {
+		case "ConfigMap", "Secret", "Projected", "DownwardAPI":
+			// subPath mounts never receive updates
+			subPathColour = colourWarn
+		}
+	}
+
+	propagation = string(v1.MountPropagationNone)
+	if mount.MountPropagation != nil {
+		propagation = string(*mount.MountPropagation)
+		if *mount.MountPropagation == v1.MountPropagationBidirectional {
+			propagationColour = colourBad
+		}
+	}
+
+	if mount.RecursiveReadOnly != nil {
+		recursiveRO = string(*mount.RecursiveReadOnly)
+	}
+
+	for _, vol := range info.Data.pod.Spec.Volumes {
+		if vol.Name != mount.Name {
+			continue
+		}
+
+		switch {
+		case vol.ConfigMap != nil:
+			defaultMode = vol.ConfigMap.DefaultMode
+			keySources = append(keySources, volumeKeySource{
+				kind:     "ConfigMap",
+				name:     vol.ConfigMap.Name,
+				optional: vol.ConfigMap.Optional != nil && *vol.ConfigMap.Optional,
+				items:    vol.ConfigMap.Items,
+			})
+
+		case vol.Secret != nil:
+			defaultMode = vol.Secret.DefaultMode
+			keySources = append(keySources, volumeKeySource{
+				kind:     "Secret",
+				name:     vol.Secret.SecretName,
+				optional: vol.Secret.Optional != nil && *vol.Secret.Optional,
+				items:    vol.Secret.Items,
+			})
+
+		case vol.Projected != nil:
+			defaultMode = vol.Projected.DefaultMode
+			for _, source := range vol.Projected.Sources {
+				if source.ConfigMap != nil {
+					keySources = append(keySources, volumeKeySource{
+						kind:     "ConfigMap",
+						name:     source.ConfigMap.Name,
+						optional: source.ConfigMap.Optional != nil && *source.ConfigMap.Optional,
+						items:    source.ConfigMap.Items,
+					})
+				}
+				if source.Secret != nil {
+					keySources = append(keySources, volumeKeySource{
+						kind:     "Secret",
+						name:     source.Secret.Name,
+						optional: source.Secret.Optional != nil && *source.Secret.Optional,
+						items:    source.Secret.Items,
+					})
+				}
+			}
+
+		case vol.DownwardAPI != nil:
+			defaultMode = vol.DownwardAPI.DefaultMode
+		}
+	}
+
+	if defaultMode != nil {
+		mode = fmt.Sprintf("%04o", *defaultMode)
+	}
+
+	itemList := []string{}
+	missingList := []string{}
+	missingKnown := true
+	for _, source := range keySources {
+		for _, item := range source.items {
+			itemStr := item.Key + "->" + item.Path
+			if item.Mode != nil {
+				itemStr += fmt.Sprintf("(%04o)", *item.Mode)
+			}
+			itemList = append(itemList, itemStr)
+		}
+
+		missing, known := s.missingKeys(info.Data.pod.Namespace, source)
+		if !known {
+			missingKnown = false
+		}
+		if len(missing) > 0 {
+			missingList = append(missingList, missing...)
+			if source.optional {
+				if missingColour != colourBad {
+					missingColour = colourWarn
+				}
+			} else {
+				missingColour = colourBad
+			}
+		}
+	}
+
+	missingCell := NewCellColourText(missingColour, strings.Join(missingList, ","))
+	if !missingKnown {
+		// we couldnt read one of the objects so we dont know which keys are missing
+		missingCell = NewCellText("unknown")
+	}
+
+	return []Cell{
+		NewCellColourText(subPathColour, subPath),
+		NewCellColourText(propagationColour, propagation),
+		NewCellText(recursiveRO),
+		NewCellText(mode),
+		NewCellText(strings.Join(itemList, ",")),
+		missingCell,
+	}
+}
+
+// missingKeys checks the keys listed in the volume items exist in the live ConfigMap or Secret, returns
+// a list of missing keys in the form name:key or just the name if the whole object is missing, false
+// is returned when the object could not be read
+func (s *volumes) missingKeys(namespace string, source volumeKeySource) ([]string, bool) {
+	log := logger{location: "volumes:missingKeys"}
+
+	var keyList []string
+	var found bool
+	var err error
+
+	missingList := []string{}
+
+	// we cant check when reading from a file
+	if s.Connection == nil || len(source.name) == 0 {
+		return missingList, true
+	}
+
+	if source.kind == "Secret" {
+		keyList, found, err = s.Connection.GetSecretKeys(source.name, namespace)
+	} else {
+		keyList, found, err = s.Connection.GetConfigMapKeys(source.name, namespace)
+	}
+
+	if err != nil {
+		log.Debug(err)
+		return missingList, false
+	}
+
+	if !found {
+		return append(missingList, source.name), true
+	}
+
+	for _, item := range source.items {
+		exists := false
+		for _, key := range keyList {
+			if key == item.Key {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			missingList = append(missingList, source.name+":"+item.Key)
+		}
+	}
+
+	return missingList, true
+}
+
+// addClaimTemplates adds a volume entry for each volumeClaimTemplate of the StatefulSet that owns the pod
+// if the pod dosent already have the volume, the claim is named <template>-<pod name> which is how the
+// StatefulSet controller names the per-ordinal claims
+func (s *volumes) addClaimTemplates(pod v1.Pod, podVolumes map[string]map[string]Cell) {
+	if !s.ShowClaims || s.Connection == nil {
+		return
+	}
+
+	for _, owner := range pod.GetOwnerReferences() {
+		if owner.Kind != TypeNameStatefulSet {
+			continue
+		}
+
+		stateful := s.Connection.GetStatefulSet(owner.Name, pod.Namespace)
+		if stateful == nil {
+			continue
+		}
+
+		for _, template := range stateful.Spec.VolumeClaimTemplates {
+			if _, ok := podVolumes[template.Name]; ok {
+				continue
+			}
+			claimName := template.Name + "-" + pod.Name
+			podVolumes[template.Name] = map[string]Cell{
+				"type":    NewCellText("VolumeClaimTemplate"),
+				"backing": NewCellText(claimName),
+				"size":    {},
+				"claim":   NewCellText(claimName),
+			}
+		}
+	}
+}
+
+// claimBuildCells follows the named claim to its bound PersistentVolume and StorageClass, returns the cells
+// "PV","REQUEST","CAPACITY","ACCESS","VOLUME-MODE","PHASE","RECLAIM","CLASS","PROVISIONER","CSI-DRIVER","VOLUME-HANDLE","EXPANDABLE"
+func (s *volumes) claimBuildCells(namespace string, claimName string) []Cell {
+	var pvName, reclaim, className, provisioner, csiDriver, volumeHandle, expandable string
+	cellList := make([]Cell, 12)
+
+	pvc := s.Connection.GetPersistentVolumeClaim(claimName, namespace)
+	if pvc == nil {
+		cellList[5] = NewCellColourText(colourBad, "NotFound")
+		return cellList
+	}
+
+	request := pvc.Spec.Resources.Requests.Storage()
+	capacity := pvc.Status.Capacity.Storage()
+
+	accessModes := pvc.Status.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = pvc.Spec.AccessModes
+	}
+
+	volumeMode := string(v1.PersistentVolumeFilesystem)
+	if pvc.Spec.VolumeMode != nil {
+		volumeMode = string(*pvc.Spec.VolumeMode)
+	}
+
+	phaseColour := colourOk
+	switch pvc.Status.Phase {
+	case v1.ClaimPending:
+		phaseColour = colourWarn
+	case v1.ClaimLost:
+		phaseColour = colourBad
+	}
+
+	if pvc.Spec.StorageClassName != nil {
+		className = *pvc.Spec.StorageClassName
+	}
+
+	if pv := s.Connection.GetPersistentVolume(pvc.Spec.VolumeName); pv != nil {
+		pvName = pv.Name
+		reclaim = string(pv.Spec.PersistentVolumeReclaimPolicy)
+		if len(className) == 0 {
+			className = pv.Spec.StorageClassName
+		}
+		if pv.Spec.CSI != nil {
+			csiDriver = pv.Spec.CSI.Driver
+			volumeHandle = pv.Spec.CSI.VolumeHandle
+		}
+	}
+
+	expandColour := [2]int{-1, 0}
+	if sc := s.Connection.GetStorageClass(className); sc != nil {
+		provisioner = sc.Provisioner
+		allowExpansion := sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
+		expandable = fmt.Sprintf("%t", allowExpansion)
+		expandColour = setColourBoolean(allowExpansion)
+	}
+
+	// a capacity smaller than requested means a resize is still in progress
+	capacityColour := [2]int{-1, 0}
+	if !capacity.IsZero() && capacity.Cmp(*request) < 0 {
+		capacityColour = colourWarn
+	}
+
+	cellList[0] = NewCellText(pvName)
+	if !request.IsZero() {
+		cellList[1] = NewCellInt(request.String(), request.Value())
+	}
+	if !capacity.IsZero() {
+		cellList[2] = NewCellColourInt(capacityColour, capacity.String(), capacity.Value())
+	}
+	cellList[3] = NewCellText(accessModesAsString(accessModes))
+	cellList[4] = NewCellText(volumeMode)
+	cellList[5] = NewCellColourText(phaseColour, string(pvc.Status.Phase))
+	cellList[6] = NewCellText(reclaim)
+	cellList[7] = NewCellText(className)
+	cellList[8] = NewCellText(provisioner)
+	cellList[9] = NewCellText(csiDriver)
+	cellList[10] = NewCellText(volumeHandle)
+	cellList[11] = NewCellColourText(expandColour, expandable)
+
+	return cellList
+}
+
+// accessModesAsString converts the list of access modes into the short form used by kubectl
+func accessModesAsString(modes []v1.PersistentVolumeAccessMode) string {
+	shortModes := []string{}
+
+	for _, mode := range modes {
+		switch mode {
+		case v1.ReadWriteOnce:
+			shortModes = append(shortModes, "RWO")
+		case v1.ReadOnlyMany:
+			shortModes = append(shortModes, "ROX")
+		case v1.ReadWriteMany:
+			shortModes = append(shortModes, "RWX")
+		case v1.ReadWriteOncePod:
+			shortModes = append(shortModes, "RWOP")
+		default:
+			shortModes = append(shortModes, string(mode))
+		}
+	}
+
+	return strings.Join(shortModes, ",")
+}
+
 func (s *volumes) mountsBuildRow(mountInfo v1.VolumeDevice) []Cell {
 	var cellList []Cell
 
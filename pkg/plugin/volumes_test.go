@@ -0,0 +1,384 @@
+package plugin
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	a1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// *****************
+// decodeVolumeType
+// *****************
+
+// every field in v1.VolumeSource must be decoded, unknown types are shown as <Type> so this
+// test will fail when a new volume source is added to the api
+func TestDecodeVolumeTypeAllSources(t *testing.T) {
+	vol := volumes{}
+
+	sourceType := reflect.TypeOf(v1.VolumeSource{})
+	for i := 0; i < sourceType.NumField(); i++ {
+		field := sourceType.Field(i)
+
+		source := v1.VolumeSource{}
+		reflect.ValueOf(&source).Elem().Field(i).Set(reflect.New(field.Type.Elem()))
+
+		output := vol.decodeVolumeType(field.Name, source)
+		if output == nil {
+			t.Errorf("Output for %s was nil", field.Name)
+			continue
+		}
+		if strings.HasPrefix(output["type"].text, "<") {
+			t.Errorf("Volume type %s is not decoded", field.Name)
+		}
+	}
+}
+
+type decodeVolumeTypeTest struct {
+	arg1            string
+	arg2            v1.VolumeSource
+	expectedType    string
+	expectedBacking string
+	expectedSize    string
+}
+
+var sizeLimit = apires.MustParse("1Gi")
+
+var decodeVolumeTypeTests = []decodeVolumeTypeTest{
+	{"CSI", v1.VolumeSource{CSI: &v1.CSIVolumeSource{Driver: "inline.csi.k8s.io", VolumeAttributes: map[string]string{"size": "2Gi"}}}, "CSI", "inline.csi.k8s.io", "2Gi"},
+	{"CephFS", v1.VolumeSource{CephFS: &v1.CephFSVolumeSource{Monitors: []string{"10.0.0.1:6789", "10.0.0.2:6789"}}}, "CephFS", "10.0.0.1:6789,10.0.0.2:6789:/", ""},
+	{"EmptyDir", v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory, SizeLimit: &sizeLimit}}, "EmptyDir", "Memory", "1Gi"},
+	{"FC", v1.VolumeSource{FC: &v1.FCVolumeSource{WWIDs: []string{"wwid1"}}}, "FC", "wwid1", ""},
+	{"FlexVolume", v1.VolumeSource{FlexVolume: &v1.FlexVolumeSource{Driver: "example/lvm"}}, "FlexVolume", "example/lvm", ""},
+	{"GitRepo", v1.VolumeSource{GitRepo: &v1.GitRepoVolumeSource{Repository: "https://example.com/repo.git", Revision: "main"}}, "GitRepo", "https://example.com/repo.git@main", ""},
+	{"Glusterfs", v1.VolumeSource{Glusterfs: &v1.GlusterfsVolumeSource{EndpointsName: "glusterfs-cluster", Path: "kube_vol"}}, "Glusterfs", "glusterfs-cluster:kube_vol", ""},
+	{"Image", v1.VolumeSource{Image: &v1.ImageVolumeSource{Reference: "quay.io/crio/artifact:v1"}}, "Image", "quay.io/crio/artifact:v1", ""},
+	{"Projected", v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: []v1.VolumeProjection{
+		{ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: v1.LocalObjectReference{Name: "config"}}},
+		{Secret: &v1.SecretProjection{LocalObjectReference: v1.LocalObjectReference{Name: "creds"}}},
+		{ServiceAccountToken: &v1.ServiceAccountTokenProjection{Path: "token"}},
+	}}}, "Projected", "config,creds,serviceAccountToken", ""},
+	{"ScaleIO", v1.VolumeSource{ScaleIO: &v1.ScaleIOVolumeSource{System: "scaleio", VolumeName: "vol-0"}}, "ScaleIO", "scaleio/vol-0", ""},
+	{"NotAVolumeType", v1.VolumeSource{}, "<NotAVolumeType>", "", ""},
+}
+
+func TestDecodeVolumeType(t *testing.T) {
+	vol := volumes{}
+
+	for _, test := range decodeVolumeTypeTests {
+		output := vol.decodeVolumeType(test.arg1, test.arg2)
+		if output["type"].text != test.expectedType {
+			t.Errorf("Output type %s not equal to expected %s", output["type"].text, test.expectedType)
+		}
+		if output["backing"].text != test.expectedBacking {
+			t.Errorf("Output backing %s not equal to expected %s, for type %s", output["backing"].text, test.expectedBacking, test.arg1)
+		}
+		if output["size"].text != test.expectedSize {
+			t.Errorf("Output size %s not equal to expected %s, for type %s", output["size"].text, test.expectedSize, test.arg1)
+		}
+	}
+}
+
+// *****************
+// accessModesAsString
+// *****************
+
+type accessModesAsStringTest struct {
+	arg1     []v1.PersistentVolumeAccessMode
+	expected string
+}
+
+var accessModesAsStringTests = []accessModesAsStringTest{
+	{nil, ""},
+	{[]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}, "RWO"},
+	{[]v1.PersistentVolumeAccessMode{v1.ReadOnlyMany}, "ROX"},
+	{[]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}, "RWX"},
+	{[]v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod}, "RWOP"},
+	{[]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce, v1.ReadOnlyMany}, "RWO,ROX"},
+	{[]v1.PersistentVolumeAccessMode{"NewMode"}, "NewMode"},
+}
+
+func TestAccessModesAsString(t *testing.T) {
+	for _, test := range accessModesAsStringTests {
+		output := accessModesAsString(test.arg1)
+		if output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+}
+
+// *****************
+// claimBuildCells
+// *****************
+
+// volumesTestConnector returns a connector with the claim, volume and storage class caches filled so
+// no requests are made to a cluster
+func volumesTestConnector() *Connector {
+	expand := true
+	fast := "fast"
+	block := v1.PersistentVolumeBlock
+
+	claim := func(name string, volumeName string, phase v1.PersistentVolumeClaimPhase, request string, capacity string) v1.PersistentVolumeClaim {
+		pvc := v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+				StorageClassName: &fast,
+				VolumeName:       volumeName,
+				Resources:        v1.VolumeResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: apires.MustParse(request)}},
+			},
+			Status: v1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+		if len(capacity) > 0 {
+			pvc.Status.Capacity = v1.ResourceList{v1.ResourceStorage: apires.MustParse(capacity)}
+			pvc.Status.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod}
+		}
+		return pvc
+	}
+
+	resizing := claim("resizing", "pv-resizing", v1.ClaimBound, "20Gi", "10Gi")
+	resizing.Spec.VolumeMode = &block
+
+	return &Connector{
+		claimList: map[string][]v1.PersistentVolumeClaim{
+			"default": {
+				claim("bound", "pv-bound", v1.ClaimBound, "10Gi", "10Gi"),
+				claim("pending", "", v1.ClaimPending, "5Gi", ""),
+				claim("lost", "pv-deleted", v1.ClaimLost, "1Gi", "1Gi"),
+				resizing,
+			},
+		},
+		volumeList: []v1.PersistentVolume{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-bound"},
+				Spec: v1.PersistentVolumeSpec{
+					PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete,
+					PersistentVolumeSource:        v1.PersistentVolumeSource{CSI: &v1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-123"}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-resizing"},
+				Spec:       v1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain},
+			},
+		},
+		storageList: []storagev1.StorageClass{
+			{ObjectMeta: metav1.ObjectMeta{Name: "fast"}, Provisioner: "ebs.csi.aws.com", AllowVolumeExpansion: &expand},
+		},
+	}
+}
+
+type claimBuildCellsTest struct {
+	claim    string
+	expected []string // "PV","REQUEST","CAPACITY","ACCESS","VOLUME-MODE","PHASE","RECLAIM","CLASS","PROVISIONER","CSI-DRIVER","VOLUME-HANDLE","EXPANDABLE"
+	phase    [2]int
+	capacity [2]int
+}
+
+var claimBuildCellsTests = []claimBuildCellsTest{
+	{"bound", []string{"pv-bound", "10Gi", "10Gi", "RWOP", "Filesystem", "Bound", "Delete", "fast", "ebs.csi.aws.com", "ebs.csi.aws.com", "vol-123", "true"}, colourOk, [2]int{-1, 0}},
+	{"pending", []string{"", "5Gi", "", "RWO", "Filesystem", "Pending", "", "fast", "ebs.csi.aws.com", "", "", "true"}, colourWarn, [2]int{}},
+	// the claim is lost as its volume has been deleted
+	{"lost", []string{"", "1Gi", "1Gi", "RWOP", "Filesystem", "Lost", "", "fast", "ebs.csi.aws.com", "", "", "true"}, colourBad, [2]int{-1, 0}},
+	// capacity smaller than the request means the resize has not finished
+	{"resizing", []string{"pv-resizing", "20Gi", "10Gi", "RWOP", "Block", "Bound", "Retain", "fast", "ebs.csi.aws.com", "", "", "true"}, colourOk, colourWarn},
+	{"missing", []string{"", "", "", "", "", "NotFound", "", "", "", "", "", ""}, colourBad, [2]int{}},
+}
+
+func TestClaimBuildCells(t *testing.T) {
+	vol := volumes{Connection: volumesTestConnector(), ShowClaims: true}
+
+	for _, test := range claimBuildCellsTests {
+		output := vol.claimBuildCells("default", test.claim)
+		for i, expected := range test.expected {
+			if output[i].text != expected {
+				t.Errorf("Output column %d %s not equal to expected %s, for claim %s", i, output[i].text, expected, test.claim)
+			}
+		}
+		if output[5].colour != test.phase {
+			t.Errorf("Output phase colour %v not equal to expected %v, for claim %s", output[5].colour, test.phase, test.claim)
+		}
+		if output[2].colour != test.capacity {
+			t.Errorf("Output capacity colour %v not equal to expected %v, for claim %s", output[2].colour, test.capacity, test.claim)
+		}
+	}
+}
+
+// *****************
+// addClaimTemplates
+// *****************
+
+func TestAddClaimTemplates(t *testing.T) {
+	connect := volumesTestConnector()
+	connect.statefulList = map[string][]a1.StatefulSet{
+		"default": {{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: a1.StatefulSetSpec{VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "logs"}},
+			}},
+		}},
+	}
+
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            "web-0",
+		Namespace:       "default",
+		OwnerReferences: []metav1.OwnerReference{{Kind: TypeNameStatefulSet, Name: "web"}},
+	}}
+
+	// the pod already lists the data volume so only logs is added
+	podVolumes := map[string]map[string]Cell{"data": {"claim": NewCellText("data-web-0")}}
+	vol := volumes{Connection: connect, ShowClaims: true}
+	vol.addClaimTemplates(pod, podVolumes)
+
+	if len(podVolumes) != 2 {
+		t.Fatalf("Output %d volumes not equal to expected 2", len(podVolumes))
+	}
+	if podVolumes["logs"]["claim"].text != "logs-web-0" || podVolumes["logs"]["type"].text != "VolumeClaimTemplate" {
+		t.Errorf("Output logs volume %s %s not equal to expected VolumeClaimTemplate logs-web-0", podVolumes["logs"]["type"].text, podVolumes["logs"]["claim"].text)
+	}
+
+	// templates are only followed when using --claims
+	podVolumes = map[string]map[string]Cell{}
+	vol.ShowClaims = false
+	vol.addClaimTemplates(pod, podVolumes)
+	if len(podVolumes) != 0 {
+		t.Errorf("Output %d volumes not equal to expected 0 without --claims", len(podVolumes))
+	}
+}
+
+// *****************
+// newObjectKeyList
+// *****************
+
+func TestNewObjectKeyList(t *testing.T) {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "config")
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "creds", errors.New("no access"))
+
+	tests := []struct {
+		name     string
+		err      error
+		keys     int
+		found    bool
+		hasError bool
+	}{
+		{"found", nil, 2, true, false},
+		{"not found", notFound, 0, false, false},
+		{"forbidden", forbidden, 0, false, true},
+	}
+
+	for _, test := range tests {
+		output := newObjectKeyList("ConfigMap/default/config", []string{"a", "b"}, test.err)
+		if len(output.keys) != test.keys || output.found != test.found || (output.err != nil) != test.hasError {
+			t.Errorf("%s: output %d keys found %t error %v not equal to expected %d keys found %t error %t", test.name, len(output.keys), output.found, output.err, test.keys, test.found, test.hasError)
+		}
+	}
+}
+
+// *****************
+// detailBuildCells
+// *****************
+
+func TestDetailBuildCells(t *testing.T) {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "gone")
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "locked", errors.New("no access"))
+
+	connect := &Connector{objectKeys: map[string]objectKeyList{
+		"ConfigMap/default/app-config": newObjectKeyList("ConfigMap/default/app-config", []string{"a", "c"}, nil),
+		"Secret/default/app-creds":     newObjectKeyList("Secret/default/app-creds", []string{"user"}, nil),
+		"Secret/default/gone":          newObjectKeyList("Secret/default/gone", nil, notFound),
+		"ConfigMap/default/locked":     newObjectKeyList("ConfigMap/default/locked", nil, forbidden),
+	}}
+
+	configMode := int32(0440)
+	projectedMode := int32(0644)
+	itemMode := int32(0600)
+	optional := true
+	bidirectional := v1.MountPropagationBidirectional
+
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: v1.PodSpec{Volumes: []v1.Volume{
+			{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: "app-config"},
+				DefaultMode:          &configMode,
+				Items:                []v1.KeyToPath{{Key: "a", Path: "a.txt"}, {Key: "b", Path: "b.txt", Mode: &itemMode}},
+			}}},
+			{Name: "creds", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{
+				SecretName: "app-creds",
+				Optional:   &optional,
+				Items:      []v1.KeyToPath{{Key: "password", Path: "password"}},
+			}}},
+			{Name: "projected", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{
+				DefaultMode: &projectedMode,
+				Sources: []v1.VolumeProjection{
+					{ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: v1.LocalObjectReference{Name: "app-config"}, Items: []v1.KeyToPath{{Key: "c", Path: "c.txt"}}}},
+					{Secret: &v1.SecretProjection{LocalObjectReference: v1.LocalObjectReference{Name: "gone"}}},
+				},
+			}}},
+			{Name: "locked", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: "locked"},
+				Items:                []v1.KeyToPath{{Key: "a", Path: "a.txt"}},
+			}}},
+			{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+		}},
+	}
+
+	noColour := [2]int{-1, 0}
+	tests := []struct {
+		name          string
+		volumeType    string
+		mount         v1.VolumeMount
+		expected      []string // "SUB-PATH","PROPAGATION","RECURSIVE-RO","MODE","ITEMS","MISSING-KEYS"
+		subPathColour [2]int
+		propColour    [2]int
+		missingColour [2]int
+	}{
+		{"configmap subPath", "ConfigMap", v1.VolumeMount{Name: "config", SubPath: "a.txt"},
+			[]string{"a.txt", "None", "", "0440", "a->a.txt,b->b.txt(0600)", "app-config:b"}, colourWarn, noColour, colourBad},
+		{"subPathExpr", "EmptyDir", v1.VolumeMount{Name: "cache", SubPathExpr: "$(POD_NAME)"},
+			[]string{"expr:$(POD_NAME)", "None", "", "", "", ""}, noColour, noColour, noColour},
+		{"bidirectional propagation", "EmptyDir", v1.VolumeMount{Name: "cache", MountPropagation: &bidirectional},
+			[]string{"", "Bidirectional", "", "", "", ""}, noColour, colourBad, noColour},
+		{"optional secret", "Secret", v1.VolumeMount{Name: "creds"},
+			[]string{"", "None", "", "", "password->password", "app-creds:password"}, noColour, noColour, colourWarn},
+		{"projected keys and missing secret", "Projected", v1.VolumeMount{Name: "projected"},
+			[]string{"", "None", "", "0644", "c->c.txt", "gone"}, noColour, noColour, colourBad},
+		{"forbidden configmap", "ConfigMap", v1.VolumeMount{Name: "locked"},
+			[]string{"", "None", "", "", "a->a.txt", "unknown"}, noColour, noColour, noColour},
+	}
+
+	vol := volumes{Connection: connect, ShowDetail: true}
+	info := BuilderInformation{Data: ParentData{pod: pod}}
+	for _, test := range tests {
+		output := vol.detailBuildCells(info, test.volumeType, test.mount)
+		for i, expected := range test.expected {
+			if output[i].text != expected {
+				t.Errorf("%s: output column %d %s not equal to expected %s", test.name, i, output[i].text, expected)
+			}
+		}
+		if output[0].colour != test.subPathColour {
+			t.Errorf("%s: output subPath colour %v not equal to expected %v", test.name, output[0].colour, test.subPathColour)
+		}
+		if output[1].colour != test.propColour {
+			t.Errorf("%s: output propagation colour %v not equal to expected %v", test.name, output[1].colour, test.propColour)
+		}
+		if output[5].colour != test.missingColour {
+			t.Errorf("%s: output missing colour %v not equal to expected %v", test.name, output[5].colour, test.missingColour)
+		}
+	}
+
+	// keys cant be checked when reading from a file
+	vol.Connection = nil
+	output := vol.detailBuildCells(info, "ConfigMap", v1.VolumeMount{Name: "config"})
+	if output[5].text != "" {
+		t.Errorf("Output missing keys %s not equal to expected empty without a connection", output[5].text)
+	}
+}